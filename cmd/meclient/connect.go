@@ -7,20 +7,55 @@ import (
 	"time"
 
 	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/orderid"
 )
 
-// connectWithTransport connects using a specific transport and protocol.
-func connectWithTransport(addr string, transport meclient.Transport, binary bool) (*meclient.Client, error) {
+// newConfig returns the client configuration shared by every connect mode.
+func newConfig(addr string, opts options) (meclient.Config, error) {
 	cfg := meclient.DefaultConfig(addr)
-	cfg.Transport = transport
-	cfg.AutoReconnect = (transport == meclient.TransportTCP)
+	cfg.Delivery = meclient.DeliverEvents
+	cfg.BlockingSends = true
 
-	if binary {
+	if opts.useBinary {
 		cfg.Protocol = meclient.ProtocolBinary
 	} else {
 		cfg.Protocol = meclient.ProtocolCSV
 	}
 
+	// Interactive kill needs to know which orders are open, and book
+	// shows the best bid/offer
+	cfg.TrackOrders = opts.interactive
+	cfg.TrackBook = opts.interactive
+	cfg.TrackPositions = true
+	cfg.TrackLatency = true
+	cfg.TrackSymbolStats = true
+
+	if opts.rate > 0 {
+		cfg.RateLimit = &meclient.RateLimits{OrdersPerSec: opts.rate}
+	}
+
+	if opts.idFile != "" {
+		ids, err := orderid.OpenFile(opts.idFile, 0)
+		if err != nil {
+			return cfg, fmt.Errorf("order ID file: %w", err)
+		}
+		cfg.OrderIDs = ids
+	}
+
+	return cfg, nil
+}
+
+// connectWithTransport connects using a specific transport and protocol.
+func connectWithTransport(addr string, transport meclient.Transport, opts options) (*meclient.Client, error) {
+	binary := opts.useBinary
+
+	cfg, err := newConfig(addr, opts)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Transport = transport
+	cfg.AutoReconnect = (transport == meclient.TransportTCP)
+
 	transportStr := "TCP"
 	if transport == meclient.TransportUDP {
 		transportStr = "UDP"
@@ -45,19 +80,18 @@ func connectWithTransport(addr string, transport meclient.Transport, binary bool
 }
 
 // connectWithFallback tries TCP first, falls back to UDP if TCP fails.
-func connectWithFallback(addr string, binary bool) (*meclient.Client, error) {
+func connectWithFallback(addr string, opts options) (*meclient.Client, error) {
+	binary := opts.useBinary
+
 	fmt.Printf("Connecting to %s via TCP...\n", addr)
 
-	cfg := meclient.DefaultConfig(addr)
+	cfg, err := newConfig(addr, opts)
+	if err != nil {
+		return nil, err
+	}
 	cfg.Transport = meclient.TransportTCP
 	cfg.ConnectTimeout = 2 * time.Second
 
-	if binary {
-		cfg.Protocol = meclient.ProtocolBinary
-	} else {
-		cfg.Protocol = meclient.ProtocolCSV
-	}
-
 	client, err := meclient.New(cfg)
 	if err != nil {
 		return nil, err
@@ -4,10 +4,12 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient"
 )
@@ -19,7 +21,6 @@ func runInteractive(client *meclient.Client, defaultUserID uint32, shutdown <-ch
 	fmt.Println()
 
 	scanner := bufio.NewScanner(os.Stdin)
-	orderID := uint32(1000)
 
 	for {
 		fmt.Print("> ")
@@ -50,28 +51,32 @@ func runInteractive(client *meclient.Client, defaultUserID uint32, shutdown <-ch
 			return
 
 		case "buy", "b":
-			order, err := parseOrderCommand(parts, meclient.SideBuy, defaultUserID, &orderID)
+			order, err := parseOrderCommand(parts, meclient.SideBuy, defaultUserID)
 			if err != nil {
 				fmt.Printf("Error: %v\n", err)
 				continue
 			}
-			fmt.Printf("Sending BUY %s %d @ %d (oid=%d)\n",
-				order.Symbol, order.Qty, order.Price, order.OrderID)
-			if err := client.SendOrder(order); err != nil {
+			orderID, err := client.SendOrder(order)
+			if err != nil {
 				fmt.Printf("Send error: %v\n", err)
+				continue
 			}
+			fmt.Printf("Sent BUY %s %d @ %d (oid=%d)\n",
+				order.Symbol, order.Qty, order.Price, orderID)
 
 		case "sell", "s":
-			order, err := parseOrderCommand(parts, meclient.SideSell, defaultUserID, &orderID)
+			order, err := parseOrderCommand(parts, meclient.SideSell, defaultUserID)
 			if err != nil {
 				fmt.Printf("Error: %v\n", err)
 				continue
 			}
-			fmt.Printf("Sending SELL %s %d @ %d (oid=%d)\n",
-				order.Symbol, order.Qty, order.Price, order.OrderID)
-			if err := client.SendOrder(order); err != nil {
+			orderID, err := client.SendOrder(order)
+			if err != nil {
 				fmt.Printf("Send error: %v\n", err)
+				continue
 			}
+			fmt.Printf("Sent SELL %s %d @ %d (oid=%d)\n",
+				order.Symbol, order.Qty, order.Price, orderID)
 
 		case "cancel", "c":
 			cancel, err := parseCancelCommand(parts, defaultUserID)
@@ -90,12 +95,25 @@ func runInteractive(client *meclient.Client, defaultUserID uint32, shutdown <-ch
 				fmt.Printf("Send error: %v\n", err)
 			}
 
+		case "book":
+			printBook(client, parts)
+
+		case "kill", "k":
+			runKill(client, parts)
+
+		case "resume":
+			client.Resume()
+			fmt.Println("Trading resumed")
+
 		case "status", "stat":
 			if client.IsConnected() {
-				fmt.Println("Connected")
+				fmt.Printf("Connected (protocol: %s)\n", client.Protocol())
 			} else {
 				fmt.Println("Disconnected")
 			}
+			if client.Halted() {
+				fmt.Println("  Trading HALTED (type 'resume' to continue)")
+			}
 			stats := client.Stats()
 			fmt.Printf("  Sent: %d  Received: %d  Errors: %d  Dropped: %d\n",
 				stats.MessagesSent, stats.MessagesReceived,
@@ -107,7 +125,89 @@ func runInteractive(client *meclient.Client, defaultUserID uint32, shutdown <-ch
 	}
 }
 
-func parseOrderCommand(parts []string, side meclient.Side, defaultUserID uint32, nextOrderID *uint32) (meclient.NewOrder, error) {
+// printBook prints the best bid/offer for one symbol, or for every
+// symbol seen so far.
+func printBook(client *meclient.Client, parts []string) {
+	var quotes []meclient.BookQuote
+	if len(parts) >= 2 {
+		symbol := strings.ToUpper(parts[1])
+		q, ok := client.Book().Quote(symbol)
+		if !ok {
+			fmt.Printf("No book for %s\n", symbol)
+			return
+		}
+		quotes = append(quotes, q)
+	} else {
+		quotes = client.Book().Quotes()
+	}
+
+	if len(quotes) == 0 {
+		fmt.Println("No book updates received")
+		return
+	}
+	for _, q := range quotes {
+		fmt.Println(formatQuote(q))
+	}
+}
+
+// formatQuote renders a quote as one line: symbol, bid, ask, spread and mid.
+func formatQuote(q meclient.BookQuote) string {
+	side := func(qty, price uint32) string {
+		if qty == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%d @ %d", qty, price)
+	}
+
+	line := fmt.Sprintf("  %-8s bid %-12s ask %-12s", q.Symbol, side(q.Bid.Qty, q.Bid.Price), side(q.Ask.Qty, q.Ask.Price))
+	if spread, ok := q.Spread(); ok {
+		line += fmt.Sprintf(" spread %d", spread)
+	}
+	if mid, ok := q.Mid(); ok {
+		line += fmt.Sprintf(" mid %g", mid)
+	}
+	return strings.TrimRight(line, " ")
+}
+
+// defaultKillTimeout is how long kill waits for cancel acks.
+const defaultKillTimeout = 5 * time.Second
+
+// runKill halts trading, cancels every open order and prints the outcome.
+func runKill(client *meclient.Client, parts []string) {
+	timeout := defaultKillTimeout
+	if len(parts) >= 2 {
+		secs, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || secs <= 0 {
+			fmt.Printf("Error: invalid timeout: %s\n", parts[1])
+			return
+		}
+		timeout = time.Duration(secs * float64(time.Second))
+	}
+
+	fmt.Println("KILL: halting trading and cancelling open orders...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	report, err := client.KillSwitch(ctx)
+	fmt.Printf("KILL: %d cancels sent, %d orders closed in %v\n",
+		report.CancelsSent, report.Closed, report.Elapsed.Round(time.Millisecond))
+	for _, sendErr := range report.SendErrors {
+		fmt.Printf("  send error: %v\n", sendErr)
+	}
+	for _, o := range report.Residual {
+		fmt.Printf("  RESIDUAL %s user=%d oid=%d %s remaining=%d\n",
+			o.Symbol, o.UserID, o.OrderID, o.State, o.Remaining())
+	}
+	if err != nil {
+		fmt.Printf("KILL: incomplete: %v\n", err)
+	}
+	fmt.Println("Trading halted (type 'resume' to continue)")
+}
+
+// parseOrderCommand builds an order from a buy or sell command. The order
+// ID is left zero for the client to assign.
+func parseOrderCommand(parts []string, side meclient.Side, defaultUserID uint32) (meclient.NewOrder, error) {
 	if len(parts) < 4 {
 		return meclient.NewOrder{}, fmt.Errorf("usage: %s SYMBOL QTY PRICE [USER_ID]", parts[0])
 	}
@@ -136,16 +236,12 @@ func parseOrderCommand(parts []string, side meclient.Side, defaultUserID uint32,
 		userID = uint32(u)
 	}
 
-	orderID := *nextOrderID
-	*nextOrderID++
-
 	return meclient.NewOrder{
-		UserID:  userID,
-		Symbol:  symbol,
-		Price:   uint32(price),
-		Qty:     uint32(qty),
-		Side:    side,
-		OrderID: orderID,
+		UserID: userID,
+		Symbol: symbol,
+		Price:  uint32(price),
+		Qty:    uint32(qty),
+		Side:   side,
 	}, nil
 }
 
@@ -180,6 +276,9 @@ func printHelp() {
 	fmt.Println("  sell SYMBOL QTY PRICE [USER_ID]   Place sell order")
 	fmt.Println("  cancel ORDER_ID [USER_ID]         Cancel order")
 	fmt.Println("  flush                             Flush all order books")
+	fmt.Println("  book [SYMBOL]                     Show best bid/offer")
+	fmt.Println("  kill [SECONDS]                    Halt trading, cancel all open orders")
+	fmt.Println("  resume                            Resume trading after kill")
 	fmt.Println("  status                            Show connection status")
 	fmt.Println("  help                              Show this help")
 	fmt.Println("  quit                              Exit")
@@ -189,5 +288,5 @@ func printHelp() {
 	fmt.Println("  sell AAPL 50 175     Sell 50 AAPL @ 175")
 	fmt.Println("  cancel 1001          Cancel order 1001")
 	fmt.Println()
-	fmt.Println("Shortcuts: b=buy, s=sell, c=cancel, f=flush, h=help, q=quit")
+	fmt.Println("Shortcuts: b=buy, s=sell, c=cancel, f=flush, k=kill, h=help, q=quit")
 }
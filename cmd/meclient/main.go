@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -101,6 +102,8 @@ type options struct {
 	useTCP      bool
 	useBinary   bool
 	userID      uint32
+	rate        float64 // Orders/sec limit; 0 is unlimited
+	idFile      string  // Persists order IDs across runs when set
 }
 
 func parseArgs(args []string) options {
@@ -133,6 +136,18 @@ func parseArgs(args []string) options {
 						opts.userID = uint32(u)
 					}
 				}
+			case "rate":
+				if i+1 < len(args) {
+					i++
+					if r, err := strconv.ParseFloat(args[i], 64); err == nil && r > 0 {
+						opts.rate = r
+					}
+				}
+			case "id-file":
+				if i+1 < len(args) {
+					i++
+					opts.idFile = args[i]
+				}
 			}
 		} else {
 			positional = append(positional, arg)
@@ -156,12 +171,12 @@ func parseArgs(args []string) options {
 
 func connect(addr string, opts options) (*meclient.Client, error) {
 	if opts.useUDP {
-		return connectWithTransport(addr, meclient.TransportUDP, opts.useBinary)
+		return connectWithTransport(addr, meclient.TransportUDP, opts)
 	}
 	if opts.useTCP {
-		return connectWithTransport(addr, meclient.TransportTCP, opts.useBinary)
+		return connectWithTransport(addr, meclient.TransportTCP, opts)
 	}
-	return connectWithFallback(addr, opts.useBinary)
+	return connectWithFallback(addr, opts)
 }
 
 func runMode(client *meclient.Client, opts options, shutdown <-chan os.Signal) {
@@ -190,34 +205,11 @@ func receiveMessages(client *meclient.Client, done <-chan struct{}) {
 		case <-done:
 			return
 
-		case ack, ok := <-client.Acks():
-			if !ok {
-				return
-			}
-			fmt.Printf("[ACK] %s user=%d order=%d\n", ack.Symbol, ack.UserID, ack.OrderID)
-
-		case trade, ok := <-client.Trades():
-			if !ok {
-				return
-			}
-			fmt.Printf("[TRADE] %s buy(user=%d,oid=%d) sell(user=%d,oid=%d) price=%d qty=%d\n",
-				trade.Symbol,
-				trade.BuyUserID, trade.BuyOrderID,
-				trade.SellUserID, trade.SellOrderID,
-				trade.Price, trade.Qty)
-
-		case update, ok := <-client.BookUpdates():
-			if !ok {
-				return
-			}
-			fmt.Printf("[BOOK] %s %s price=%d qty=%d\n",
-				update.Symbol, update.Side, update.Price, update.Qty)
-
-		case cancelAck, ok := <-client.CancelAcks():
+		case ev, ok := <-client.Events():
 			if !ok {
 				return
 			}
-			fmt.Printf("[CANCEL] %s user=%d order=%d\n", cancelAck.Symbol, cancelAck.UserID, cancelAck.OrderID)
+			printEvent(ev)
 
 		case err, ok := <-client.Errors():
 			if !ok {
@@ -230,6 +222,45 @@ func receiveMessages(client *meclient.Client, done <-chan struct{}) {
 				return
 			}
 			fmt.Printf("[RECONNECT] Connected after %d attempts\n", event.Attempt)
+			if event.UnknownOrders > 0 {
+				fmt.Printf("[RECONNECT] %d orders were open at disconnect, %d cancels sent\n",
+					event.UnknownOrders, event.CancelsSent)
+			}
+		}
+	}
+}
+
+// printEvent prints one inbound message.
+func printEvent(ev meclient.Event) {
+	switch ev.Kind {
+	case meclient.EventAck:
+		ack := ev.Ack
+		fmt.Printf("[ACK] %s user=%d order=%d\n", ack.Symbol, ack.UserID, ack.OrderID)
+
+	case meclient.EventTrade:
+		trade := ev.Trade
+		fmt.Printf("[TRADE] %s buy(user=%d,oid=%d) sell(user=%d,oid=%d) price=%d qty=%d\n",
+			trade.Symbol,
+			trade.BuyUserID, trade.BuyOrderID,
+			trade.SellUserID, trade.SellOrderID,
+			trade.Price, trade.Qty)
+
+	case meclient.EventBookUpdate:
+		update := ev.BookUpdate
+		fmt.Printf("[BOOK] %s %s price=%d qty=%d\n",
+			update.Symbol, update.Side, update.Price, update.Qty)
+
+	case meclient.EventCancelAck:
+		cancelAck := ev.CancelAck
+		fmt.Printf("[CANCEL] %s user=%d order=%d\n", cancelAck.Symbol, cancelAck.UserID, cancelAck.OrderID)
+
+	case meclient.EventReject:
+		reject := ev.Reject
+		if reject.OrderID == 0 {
+			fmt.Printf("[REJECT] %s\n", reject.Reason)
+		} else {
+			fmt.Printf("[REJECT] %s user=%d order=%d reason=%q\n",
+				reject.Symbol, reject.UserID, reject.OrderID, reject.Reason)
 		}
 	}
 }
@@ -242,6 +273,76 @@ func printStats(client *meclient.Client) {
 	fmt.Printf("  Errors:            %d\n", stats.ErrorCount)
 	fmt.Printf("  Reconnects:        %d\n", stats.ReconnectCount)
 	fmt.Printf("  Dropped Messages:  %d\n", stats.DroppedMessages)
+	fmt.Printf("  Sent:              %d orders, %d cancels, %d flushes\n",
+		stats.NewOrdersSent, stats.CancelsSent, stats.FlushesSent)
+	fmt.Printf("  Received:          %d acks, %d trades, %d book, %d cancel acks, %d rejects, %d unknown\n",
+		stats.AcksReceived, stats.TradesReceived, stats.BookUpdatesReceived,
+		stats.CancelAcksReceived, stats.RejectsReceived, stats.UnknownMessages)
+	fmt.Printf("  Bytes Out/In:      %d / %d\n", stats.BytesSent, stats.BytesReceived)
+	if stats.QueueLatency.Count > 0 {
+		fmt.Printf("  Queue Latency:     %s\n", formatLatency(stats.QueueLatency))
+	}
+	if stats.AckLatency.Count > 0 {
+		fmt.Printf("  Ack Latency:       %s\n", formatLatency(stats.AckLatency))
+	}
+
+	printSymbolStats(stats.Symbols)
+
+	if keeper := client.Positions(); keeper != nil {
+		printPositions(keeper)
+	}
+}
+
+// printSymbolStats prints the message counts of each symbol.
+func printSymbolStats(symbols map[string]meclient.SymbolStats) {
+	if len(symbols) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(symbols))
+	for name := range symbols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("\nSymbols:\n")
+	for _, name := range names {
+		fmt.Println(formatSymbolStats(name, symbols[name]))
+	}
+}
+
+// formatSymbolStats renders one symbol's message counts as one line.
+func formatSymbolStats(symbol string, s meclient.SymbolStats) string {
+	return fmt.Sprintf("  %-8s sent %-6d acks %-6d trades %-6d book %-6d rejects %-6d dropped %d",
+		symbol, s.NewOrdersSent+s.CancelsSent, s.Acks+s.CancelAcks, s.Trades, s.BookUpdates, s.Rejects, s.Dropped)
+}
+
+// formatLatency renders a latency histogram's percentiles as one line.
+func formatLatency(s meclient.LatencySnapshot) string {
+	return fmt.Sprintf("p50 %v  p90 %v  p99 %v  p99.9 %v  max %v",
+		s.P50(), s.P90(), s.P99(), s.P999(), s.Max)
+}
+
+// printPositions prints each symbol's position and the total P&L.
+func printPositions(keeper *meclient.PositionKeeper) {
+	positions := keeper.Positions()
+	if len(positions) == 0 {
+		return
+	}
+
+	fmt.Printf("\nPositions:\n")
+	for _, p := range positions {
+		fmt.Println(formatPosition(p))
+	}
+	realized, unrealized := keeper.PnL()
+	fmt.Printf("  P&L: realized %.2f  unrealized %.2f  total %.2f\n",
+		realized, unrealized, realized+unrealized)
+}
+
+// formatPosition renders a position as one line.
+func formatPosition(p meclient.Position) string {
+	return fmt.Sprintf("  %-8s net %-8d avg %-10.2f realized %-10.2f unrealized %.2f",
+		p.Symbol, p.Net, p.AvgCost, p.Realized, p.Unrealized)
 }
 
 func printUsage() {
@@ -262,6 +363,8 @@ func printUsage() {
 	fmt.Println("Other Options:")
 	fmt.Println("  -v                  Verbose output")
 	fmt.Println("  -user N             Set user ID (default: 1)")
+	fmt.Println("  -rate N             Limit to N orders/sec (default: unlimited)")
+	fmt.Println("  -id-file PATH       Continue order IDs from PATH across runs")
 	fmt.Println("  -danger-burst       Allow unthrottled burst scenarios")
 	fmt.Println()
 	fmt.Println("Examples:")
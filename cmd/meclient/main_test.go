@@ -4,13 +4,12 @@ package main
 
 import (
 	"testing"
+	"time"
 
 	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient"
 )
 
 func TestParseOrderCommand_Valid(t *testing.T) {
-	var orderID uint32 = 100
-
 	tests := []struct {
 		name     string
 		parts    []string
@@ -47,8 +46,7 @@ func TestParseOrderCommand_Valid(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			oid := orderID
-			order, err := parseOrderCommand(tt.parts, tt.side, 1, &oid)
+			order, err := parseOrderCommand(tt.parts, tt.side, 1)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -66,8 +64,6 @@ func TestParseOrderCommand_Valid(t *testing.T) {
 }
 
 func TestParseOrderCommand_Invalid(t *testing.T) {
-	var orderID uint32 = 100
-
 	tests := []struct {
 		name  string
 		parts []string
@@ -81,8 +77,7 @@ func TestParseOrderCommand_Invalid(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			oid := orderID
-			_, err := parseOrderCommand(tt.parts, meclient.SideBuy, 1, &oid)
+			_, err := parseOrderCommand(tt.parts, meclient.SideBuy, 1)
 			if err == nil {
 				t.Error("expected error")
 			}
@@ -132,3 +127,66 @@ func TestParseCancelCommand_Invalid(t *testing.T) {
 		})
 	}
 }
+
+func TestParseArgs_Rate(t *testing.T) {
+	opts := parseArgs([]string{"localhost", "1234", "12", "-rate", "5000"})
+	if opts.rate != 5000 || opts.scenarioID != 12 {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+
+	opts = parseArgs([]string{"localhost", "1234", "-rate", "-1"})
+	if opts.rate != 0 {
+		t.Errorf("expected negative rate to be ignored, got %v", opts.rate)
+	}
+}
+
+func TestParseArgs_IDFile(t *testing.T) {
+	opts := parseArgs([]string{"localhost", "1234", "-i", "-id-file", "/tmp/ids"})
+	if opts.idFile != "/tmp/ids" || !opts.interactive {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+}
+
+func TestFormatQuote(t *testing.T) {
+	q := meclient.BookQuote{Symbol: "IBM"}
+	q.Bid.Price, q.Bid.Qty = 100, 50
+	q.Ask.Price, q.Ask.Qty = 105, 20
+
+	want := "  IBM      bid 50 @ 100     ask 20 @ 105     spread 5 mid 102.5"
+	if got := formatQuote(q); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	q.Ask.Price, q.Ask.Qty = 0, 0
+	want = "  IBM      bid 50 @ 100     ask -"
+	if got := formatQuote(q); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFormatLatency(t *testing.T) {
+	s := meclient.LatencySnapshot{Count: 1, Sum: 42 * time.Microsecond, Min: 42 * time.Microsecond, Max: 42 * time.Microsecond}
+
+	want := "p50 42µs  p90 42µs  p99 42µs  p99.9 42µs  max 42µs"
+	if got := formatLatency(s); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFormatSymbolStats(t *testing.T) {
+	s := meclient.SymbolStats{NewOrdersSent: 3, CancelsSent: 1, Acks: 3, CancelAcks: 1, Trades: 2, BookUpdates: 7, Dropped: 1}
+
+	want := "  IBM      sent 4      acks 4      trades 2      book 7      rejects 0      dropped 1"
+	if got := formatSymbolStats("IBM", s); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFormatPosition(t *testing.T) {
+	p := meclient.Position{Symbol: "IBM", Net: -50, AvgCost: 100.5, Realized: 25, Unrealized: -12.5}
+
+	want := "  IBM      net -50      avg 100.50     realized 25.00      unrealized -12.50"
+	if got := formatPosition(p); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
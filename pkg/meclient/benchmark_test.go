@@ -0,0 +1,122 @@
+// Full path: pkg/meclient/benchmark_test.go
+
+package meclient
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+// countingWriter counts bytes received by the sink server.
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	atomic.AddInt64(&w.n, int64(len(p)))
+	return len(p), nil
+}
+
+// startSinkServer accepts one connection and discards everything it
+// reads, counting the bytes.
+func startSinkServer(b *testing.B) (string, *countingWriter) {
+	b.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Fatalf("failed to start listener: %v", err)
+	}
+	b.Cleanup(func() { listener.Close() })
+
+	counter := &countingWriter{}
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := conn.Read(buf)
+			_, _ = counter.Write(buf[:n])
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return listener.Addr().String(), counter
+}
+
+// benchmarkWritePath sends b.N orders through a connected client and
+// waits until the server has received all of them.
+func benchmarkWritePath(b *testing.B, batchSize int, linger time.Duration) {
+	addr, counter := startSinkServer(b)
+
+	order := NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 50, Side: SideBuy, OrderID: 1}
+
+	var frame bytes.Buffer
+	if err := protocol.NewEncoder(&frame).EncodeNewOrder(&order); err != nil {
+		b.Fatalf("failed to encode order: %v", err)
+	}
+	frameSize := int64(frame.Len())
+
+	cfg := DefaultConfig(addr)
+	cfg.Protocol = ProtocolCSV
+	cfg.AutoReconnect = false
+	cfg.WriteBatchSize = batchSize
+	cfg.WriteLinger = linger
+	client, err := New(cfg)
+	if err != nil {
+		b.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+	if err := client.Connect(); err != nil {
+		b.Fatalf("failed to connect: %v", err)
+	}
+
+	ctx := context.Background()
+	want := frameSize * int64(b.N)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := client.SendOrderContext(ctx, order); err != nil {
+			b.Fatalf("send failed: %v", err)
+		}
+	}
+	for atomic.LoadInt64(&counter.n) < want {
+		time.Sleep(10 * time.Microsecond)
+	}
+
+	elapsed := time.Since(start)
+	b.StopTimer()
+
+	b.ReportMetric(float64(b.N)/elapsed.Seconds(), "msgs/s")
+	if batches := client.Stats().WriteBatches; batches > 0 {
+		b.ReportMetric(float64(b.N)/float64(batches), "msgs/flush")
+	}
+}
+
+// BenchmarkWritePath_Unbatched flushes every message, as the write loop
+// did before batching.
+func BenchmarkWritePath_Unbatched(b *testing.B) {
+	benchmarkWritePath(b, 1, 0)
+}
+
+func BenchmarkWritePath_Batched(b *testing.B) {
+	benchmarkWritePath(b, DefaultWriteBatchSize, 0)
+}
+
+func BenchmarkWritePath_BatchedLinger(b *testing.B) {
+	benchmarkWritePath(b, DefaultWriteBatchSize, 50*time.Microsecond)
+}
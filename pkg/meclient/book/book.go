@@ -0,0 +1,199 @@
+// Full path: pkg/meclient/book/book.go
+
+// Package book maintains per-symbol best bid and offer from BookUpdates.
+//
+// The engine reports only the top of each side, so a Book holds one
+// Quote per symbol rather than full depth. An update with no price and
+// quantity (sent on the wire as "-,-") empties that side. It is fed by
+// the client's read loop and is safe for concurrent use.
+package book
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+// Level is the best price on one side of a book. A zero Qty means the
+// side is empty.
+type Level struct {
+	Price uint32
+	Qty   uint32
+}
+
+// IsEmpty returns true if no orders rest on this side.
+func (l Level) IsEmpty() bool {
+	return l.Qty == 0
+}
+
+// Quote is a snapshot of a symbol's best bid and offer.
+type Quote struct {
+	Symbol    string
+	Bid       Level
+	Ask       Level
+	Updates   uint64 // BookUpdates applied for this symbol
+	UpdatedAt time.Time
+}
+
+// IsTwoSided returns true if both bid and ask are present.
+func (q *Quote) IsTwoSided() bool {
+	return !q.Bid.IsEmpty() && !q.Ask.IsEmpty()
+}
+
+// Spread returns ask minus bid. ok is false unless the quote is two-sided
+// and not crossed.
+func (q *Quote) Spread() (spread uint32, ok bool) {
+	if !q.IsTwoSided() || q.Ask.Price < q.Bid.Price {
+		return 0, false
+	}
+	return q.Ask.Price - q.Bid.Price, true
+}
+
+// Mid returns the midpoint of bid and ask. ok is false unless the quote
+// is two-sided.
+func (q *Quote) Mid() (mid float64, ok bool) {
+	if !q.IsTwoSided() {
+		return 0, false
+	}
+	return (float64(q.Bid.Price) + float64(q.Ask.Price)) / 2, true
+}
+
+// Event reports a change to a symbol's best bid or offer.
+type Event struct {
+	Quote Quote // Snapshot after the change
+	Prev  Quote // Snapshot before the change
+}
+
+// Book holds the latest quote for every symbol seen.
+type Book struct {
+	mu     sync.RWMutex
+	quotes map[string]*Quote
+
+	events  chan Event
+	dropped uint64
+	closed  bool
+
+	now func() time.Time
+}
+
+// New creates a book whose event channel holds buffer events.
+func New(buffer int) *Book {
+	return &Book{
+		quotes: make(map[string]*Quote),
+		events: make(chan Event, buffer),
+		now:    time.Now,
+	}
+}
+
+// Events returns the change notification channel. An event is sent only
+// when an update changes the price or quantity of a side. Events are
+// dropped when the channel is full; see Dropped.
+func (b *Book) Events() <-chan Event {
+	return b.events
+}
+
+// Dropped returns the number of events dropped because Events was full.
+func (b *Book) Dropped() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.dropped
+}
+
+// Close closes the event channel. Later updates are applied but not reported.
+func (b *Book) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.closed {
+		b.closed = true
+		close(b.events)
+	}
+}
+
+// Apply records a top-of-book update and returns true if it changed the
+// quote. Updates with an unknown side are ignored.
+func (b *Book) Apply(update protocol.BookUpdate) bool {
+	if update.Side != protocol.SideBuy && update.Side != protocol.SideSell {
+		return false
+	}
+
+	level := Level{Price: update.Price, Qty: update.Qty}
+	if level.IsEmpty() {
+		level = Level{}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q, ok := b.quotes[update.Symbol]
+	if !ok {
+		q = &Quote{Symbol: update.Symbol}
+		b.quotes[update.Symbol] = q
+	}
+
+	side := &q.Bid
+	if update.Side == protocol.SideSell {
+		side = &q.Ask
+	}
+
+	prev := *q
+	q.Updates++
+	q.UpdatedAt = b.now()
+	if *side == level {
+		return false
+	}
+	*side = level
+	b.emit(q, prev)
+	return true
+}
+
+// Quote returns the quote for symbol.
+func (b *Book) Quote(symbol string) (Quote, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	q, ok := b.quotes[symbol]
+	if !ok {
+		return Quote{}, false
+	}
+	return *q, true
+}
+
+// Quotes returns every quote, sorted by symbol.
+func (b *Book) Quotes() []Quote {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	quotes := make([]Quote, 0, len(b.quotes))
+	for _, q := range b.quotes {
+		quotes = append(quotes, *q)
+	}
+	sort.Slice(quotes, func(i, j int) bool {
+		return quotes[i].Symbol < quotes[j].Symbol
+	})
+	return quotes
+}
+
+// Reset forgets every quote, e.g. when the book is known to be stale.
+// No events are sent.
+func (b *Book) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.quotes = make(map[string]*Quote)
+}
+
+// emit sends a change event without blocking. Caller must hold b.mu.
+func (b *Book) emit(q *Quote, prev Quote) {
+	if b.closed {
+		return
+	}
+
+	select {
+	case b.events <- Event{Quote: *q, Prev: prev}:
+	default:
+		b.dropped++
+	}
+}
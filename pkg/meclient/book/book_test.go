@@ -0,0 +1,131 @@
+// Full path: pkg/meclient/book/book_test.go
+
+package book
+
+import (
+	"testing"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+func update(symbol string, side protocol.Side, price, qty uint32) protocol.BookUpdate {
+	return protocol.BookUpdate{Symbol: symbol, Side: side, Price: price, Qty: qty}
+}
+
+func TestBook_Apply(t *testing.T) {
+	b := New(16)
+
+	b.Apply(update("IBM", protocol.SideBuy, 100, 50))
+	b.Apply(update("IBM", protocol.SideSell, 104, 20))
+
+	q, ok := b.Quote("IBM")
+	if !ok {
+		t.Fatal("expected IBM quote")
+	}
+	if q.Bid != (Level{100, 50}) || q.Ask != (Level{104, 20}) || q.Updates != 2 {
+		t.Errorf("unexpected quote: %+v", q)
+	}
+	if spread, ok := q.Spread(); !ok || spread != 4 {
+		t.Errorf("expected spread 4, got %d, %v", spread, ok)
+	}
+	if mid, ok := q.Mid(); !ok || mid != 102 {
+		t.Errorf("expected mid 102, got %v, %v", mid, ok)
+	}
+
+	if _, ok := b.Quote("AAPL"); ok {
+		t.Error("expected no AAPL quote")
+	}
+}
+
+func TestBook_EmptySide(t *testing.T) {
+	b := New(16)
+
+	b.Apply(update("IBM", protocol.SideBuy, 100, 50))
+	b.Apply(update("IBM", protocol.SideSell, 101, 10))
+
+	// "B, IBM, S, -, -" decodes to zero price and quantity
+	b.Apply(update("IBM", protocol.SideSell, 0, 0))
+
+	q, _ := b.Quote("IBM")
+	if !q.Ask.IsEmpty() || q.Bid.IsEmpty() || q.IsTwoSided() {
+		t.Errorf("expected one-sided quote, got %+v", q)
+	}
+	if _, ok := q.Spread(); ok {
+		t.Error("expected no spread for a one-sided quote")
+	}
+	if _, ok := q.Mid(); ok {
+		t.Error("expected no mid for a one-sided quote")
+	}
+}
+
+func TestBook_Events(t *testing.T) {
+	b := New(16)
+
+	if !b.Apply(update("IBM", protocol.SideBuy, 100, 50)) {
+		t.Fatal("expected first update to change the quote")
+	}
+	if b.Apply(update("IBM", protocol.SideBuy, 100, 50)) {
+		t.Error("expected repeated update to be no change")
+	}
+	b.Apply(update("IBM", protocol.SideBuy, 101, 10))
+
+	ev := <-b.Events()
+	if ev.Prev.Bid != (Level{}) || ev.Quote.Bid != (Level{100, 50}) {
+		t.Errorf("unexpected first event: %+v", ev)
+	}
+	ev = <-b.Events()
+	if ev.Prev.Bid != (Level{100, 50}) || ev.Quote.Bid != (Level{101, 10}) {
+		t.Errorf("unexpected second event: %+v", ev)
+	}
+	select {
+	case ev := <-b.Events():
+		t.Errorf("unexpected event: %+v", ev)
+	default:
+	}
+}
+
+func TestBook_DroppedAndClose(t *testing.T) {
+	b := New(1)
+
+	b.Apply(update("IBM", protocol.SideBuy, 100, 1))
+	b.Apply(update("IBM", protocol.SideBuy, 101, 1))
+	if b.Dropped() != 1 {
+		t.Errorf("expected 1 dropped event, got %d", b.Dropped())
+	}
+
+	b.Close()
+	b.Close()
+	b.Apply(update("IBM", protocol.SideBuy, 102, 1))
+	if q, _ := b.Quote("IBM"); q.Bid.Price != 102 {
+		t.Errorf("expected update applied after close, got %+v", q)
+	}
+}
+
+func TestBook_QuotesAndReset(t *testing.T) {
+	b := New(16)
+
+	b.Apply(update("MSFT", protocol.SideBuy, 300, 1))
+	b.Apply(update("AAPL", protocol.SideSell, 175, 1))
+	b.Apply(update("IBM", protocol.Side('X'), 100, 1))
+
+	quotes := b.Quotes()
+	if len(quotes) != 2 || quotes[0].Symbol != "AAPL" || quotes[1].Symbol != "MSFT" {
+		t.Errorf("expected unknown side ignored, got %+v", quotes)
+	}
+
+	b.Reset()
+	if n := len(b.Quotes()); n != 0 {
+		t.Errorf("expected no quotes after reset, got %d", n)
+	}
+}
+
+func TestQuote_Crossed(t *testing.T) {
+	q := Quote{Bid: Level{105, 1}, Ask: Level{100, 1}}
+
+	if _, ok := q.Spread(); ok {
+		t.Error("expected no spread for a crossed quote")
+	}
+	if mid, ok := q.Mid(); !ok || mid != 102.5 {
+		t.Errorf("expected mid 102.5, got %v, %v", mid, ok)
+	}
+}
@@ -8,29 +8,69 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/book"
 	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/config"
 	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/internal/stats"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/latency"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/orderid"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/orders"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/position"
 	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/ratelimit"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/risk"
 	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/transport"
 )
 
 // Re-export types from subpackages for convenient access
 type (
-	Config         = config.Config
-	Transport      = config.Transport
-	Protocol       = config.Protocol
-	Side           = protocol.Side
-	NewOrder       = protocol.NewOrder
-	CancelOrder    = protocol.CancelOrder
-	Ack            = protocol.Ack
-	Trade          = protocol.Trade
-	BookUpdate     = protocol.BookUpdate
-	CancelAck      = protocol.CancelAck
-	ReconnectEvent = protocol.ReconnectEvent
-	StatsSnapshot  = stats.Snapshot
+	Config               = config.Config
+	Transport            = config.Transport
+	Protocol             = config.Protocol
+	UnknownMessagePolicy = config.UnknownMessagePolicy
+	Delivery             = config.Delivery
+	DisconnectPolicy     = config.DisconnectPolicy
+	Backpressure         = config.Backpressure
+	BackpressurePolicy   = config.BackpressurePolicy
+	Side                 = protocol.Side
+	NewOrder             = protocol.NewOrder
+	CancelOrder          = protocol.CancelOrder
+	Ack                  = protocol.Ack
+	Trade                = protocol.Trade
+	BookUpdate           = protocol.BookUpdate
+	CancelAck            = protocol.CancelAck
+	Reject               = protocol.Reject
+	RawMessage           = protocol.RawMessage
+	ReconnectEvent       = protocol.ReconnectEvent
+	Message              = protocol.Message
+	Codec                = protocol.Codec
+	MessageEncoder       = protocol.MessageEncoder
+	MessageDecoder       = protocol.MessageDecoder
+	Handler              = protocol.Handler
+	NopHandler           = protocol.NopHandler
+	StatsSnapshot        = stats.Snapshot
+	SymbolStats          = stats.SymbolSnapshot
+	OrderManager         = orders.Manager
+	OrderState           = orders.State
+	TrackedOrder         = orders.Order
+	OrderEvent           = orders.Event
+	OrderBook            = book.Book
+	BookQuote            = book.Quote
+	BookEvent            = book.Event
+	PositionKeeper       = position.Keeper
+	Position             = position.Position
+	LatencyTracker       = latency.Tracker
+	LatencySnapshot      = stats.HistogramSnapshot
+	RiskLimits           = risk.Limits
+	RiskChecker          = risk.Checker
+	RiskError            = risk.Error
+	RateLimits           = ratelimit.Limits
+	RateLimiter          = ratelimit.Limiter
+	OrderIDAllocator     = orderid.Allocator
 )
 
 // Re-export constants
@@ -45,12 +85,36 @@ const (
 	ProtocolCSV    = config.ProtocolCSV
 	ProtocolBinary = config.ProtocolBinary
 
-	DefaultPort = config.DefaultPort
+	UnknownStrict      = config.UnknownStrict
+	UnknownSkip        = config.UnknownSkip
+	UnknownPassThrough = config.UnknownPassThrough
+
+	DeliverChannels = config.DeliverChannels
+	DeliverEvents   = config.DeliverEvents
+	DeliverBoth     = config.DeliverBoth
+
+	DisconnectKeep   = config.DisconnectKeep
+	DisconnectReport = config.DisconnectReport
+	DisconnectCancel = config.DisconnectCancel
+
+	RateLimitBlock  = ratelimit.ModeBlock
+	RateLimitReject = ratelimit.ModeReject
+
+	BackpressureDefault    = config.BackpressureDefault
+	BackpressureDropNewest = config.BackpressureDropNewest
+	BackpressureDropOldest = config.BackpressureDropOldest
+	BackpressureBlock      = config.BackpressureBlock
+	BackpressureSpill      = config.BackpressureSpill
+
+	DefaultPort           = config.DefaultPort
+	DefaultWriteBatchSize = config.DefaultWriteBatchSize
 )
 
-// Re-export config functions
+// Re-export config and codec functions
 var (
 	DefaultConfig = config.Default
+	RegisterCodec = protocol.RegisterCodec
+	LookupCodec   = protocol.LookupCodec
 )
 
 // Re-export errors
@@ -60,6 +124,9 @@ var (
 	ErrSymbolTooLong = protocol.ErrSymbolTooLong
 	ErrZeroQuantity  = protocol.ErrZeroQuantity
 	ErrInvalidSide   = protocol.ErrInvalidSide
+
+	ErrUnknownMessageType = protocol.ErrUnknownMessageType
+	ErrOrderIDsExhausted  = orderid.ErrExhausted
 )
 
 // Client-specific errors
@@ -69,6 +136,9 @@ var (
 	ErrWriteQueueFull = errors.New("write queue full")
 	ErrChannelFull    = errors.New("channel full, message dropped")
 	ErrMaxReconnects  = errors.New("maximum reconnection attempts exceeded")
+	ErrRateLimited    = ratelimit.ErrRateLimited
+
+	ErrProtocolUndetected = errors.New("no reply to protocol detection probes, sending CSV")
 )
 
 // Internal write request types
@@ -81,9 +151,35 @@ const (
 )
 
 type writeRequest struct {
-	reqType writeRequestType
-	order   protocol.NewOrder
-	cancel  protocol.CancelOrder
+	reqType    writeRequestType
+	order      protocol.NewOrder
+	cancel     protocol.CancelOrder
+	enqueuedAt time.Time // Set for orders when latency is tracked; zero if the write failed
+	mustSend   bool      // Wait for the rate limiter even under RateLimitReject
+}
+
+// rateClass returns the rate limiter class and user for the request.
+func (r *writeRequest) rateClass() (ratelimit.Class, uint32) {
+	switch r.reqType {
+	case writeRequestOrder:
+		return ratelimit.ClassOrder, r.order.UserID
+	case writeRequestCancel:
+		return ratelimit.ClassCancel, r.cancel.UserID
+	default:
+		return ratelimit.ClassFlush, 0
+	}
+}
+
+// symbol returns the request's symbol, or "" for a flush.
+func (r *writeRequest) symbol() string {
+	switch r.reqType {
+	case writeRequestOrder:
+		return r.order.Symbol
+	case writeRequestCancel:
+		return r.cancel.Symbol
+	default:
+		return ""
+	}
 }
 
 // FlushableTransport extends transport with Flush capability
@@ -96,28 +192,70 @@ type FlushableTransport interface {
 type Client struct {
 	cfg config.Config
 
-	// Transport
-	transport transport.Transport
+	// Connection state. The read loop replaces it on reconnect while the
+	// write loop encodes. connMu guards the fields only: the write loop
+	// takes a snapshot and writes without it, so Close and reconnect can
+	// close a transport that a blocked write is still using.
+	connMu          sync.Mutex
+	transport       transport.Transport
+	encoder         protocol.MessageEncoder
+	encoderProtocol config.Protocol
 
 	// Protocol
-	encoder *protocol.Encoder
+	codec      protocol.Codec // explicitly configured codec, if any
+	negotiated int32          // config.Protocol, accessed atomically
+	detected   chan struct{}  // closed once auto-detection locks the protocol
+	probes     uint32         // detection probes sent, accessed atomically
 
 	// Write path
 	writeCh chan writeRequest
 
-	// Output channels
-	ackCh        chan protocol.Ack
-	tradeCh      chan protocol.Trade
-	bookUpdateCh chan protocol.BookUpdate
-	cancelAckCh  chan protocol.CancelAck
-	errorCh      chan error
-	reconnectCh  chan protocol.ReconnectEvent
+	// Output channels, each with its backpressure policy
+	acks        *outbox[protocol.Ack]
+	trades      *outbox[protocol.Trade]
+	bookUpdates *outbox[protocol.BookUpdate]
+	cancelAcks  *outbox[protocol.CancelAck]
+	rejects     *outbox[protocol.Reject]
+	unknown     *outbox[protocol.RawMessage]
+	events      *outbox[Event]
+	errorCh     chan error
+	reconnectCh chan protocol.ReconnectEvent
+
+	// Receive sequence for Events(); owned by the read loop
+	recvSeq uint64
 
 	// Lifecycle
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
 
+	// Order tracking (nil unless Config.TrackOrders)
+	orders *orders.Manager
+
+	// Best bid/offer per symbol (nil unless Config.TrackBook)
+	book *book.Book
+
+	// Positions and P&L (nil unless Config.TrackPositions)
+	positions *position.Keeper
+
+	// Orders awaiting their first response (nil unless Config.TrackLatency)
+	latency *latency.Tracker
+
+	// Pre-trade risk checks (nil unless Config.Risk)
+	risk *risk.Checker
+
+	// Outbound rate limiting (nil unless Config.RateLimit)
+	limiter *ratelimit.Limiter
+
+	// Fills in zero order IDs
+	orderIDs orderid.Allocator
+
+	// Set by KillSwitch, cleared by Resume; accessed atomically
+	halted int32
+
+	// Synchronous requests awaiting a response
+	pending *pendingRequests
+
 	// Metrics
 	stats stats.Stats
 }
@@ -130,20 +268,90 @@ func New(cfg config.Config) (*Client, error) {
 		return nil, err
 	}
 
+	var codec protocol.Codec
+	if cfg.Codec != "" {
+		var ok bool
+		if codec, ok = protocol.LookupCodec(cfg.Codec); !ok {
+			return nil, fmt.Errorf("%w: unknown codec %q", ErrInvalidConfig, cfg.Codec)
+		}
+	}
+
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit != nil {
+		var err error
+		if limiter, err = ratelimit.New(*cfg.RateLimit); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &Client{
-		cfg:          cfg,
-		writeCh:      make(chan writeRequest, cfg.ChannelBuffer),
-		ackCh:        make(chan protocol.Ack, cfg.ChannelBuffer),
-		tradeCh:      make(chan protocol.Trade, cfg.ChannelBuffer),
-		bookUpdateCh: make(chan protocol.BookUpdate, cfg.ChannelBuffer),
-		cancelAckCh:  make(chan protocol.CancelAck, cfg.ChannelBuffer),
-		errorCh:      make(chan error, cfg.ChannelBuffer),
-		reconnectCh:  make(chan protocol.ReconnectEvent, 16),
-		ctx:          ctx,
-		cancel:       cancel,
-	}, nil
+	// UDP cannot be probed, so auto mode starts out as CSV there.
+	negotiated := cfg.Protocol
+	if negotiated == config.ProtocolAuto && cfg.IsUDP() {
+		negotiated = config.ProtocolCSV
+	}
+
+	var tracker *orders.Manager
+	if cfg.TrackOrders {
+		tracker = orders.NewManager(cfg.ChannelBuffer, cfg.TerminalOrders)
+	}
+
+	var quotes *book.Book
+	if cfg.TrackBook {
+		quotes = book.New(cfg.ChannelBuffer)
+	}
+
+	var keeper *position.Keeper
+	if cfg.TrackPositions {
+		keeper = position.NewKeeper()
+	}
+
+	var timer *latency.Tracker
+	if cfg.TrackLatency {
+		timer = latency.NewTracker(0)
+	}
+
+	var checker *risk.Checker
+	if cfg.Risk != nil {
+		checker = risk.NewChecker(*cfg.Risk)
+	}
+
+	ids := cfg.OrderIDs
+	if ids == nil {
+		ids = orderid.NewTimeSeeded()
+	}
+
+	c := &Client{
+		cfg:         cfg,
+		codec:       codec,
+		negotiated:  int32(negotiated),
+		detected:    make(chan struct{}),
+		writeCh:     make(chan writeRequest, cfg.ChannelBuffer),
+		errorCh:     make(chan error, cfg.ChannelBuffer),
+		reconnectCh: make(chan protocol.ReconnectEvent, 16),
+		orders:      tracker,
+		book:        quotes,
+		positions:   keeper,
+		latency:     timer,
+		risk:        checker,
+		limiter:     limiter,
+		orderIDs:    ids,
+		pending:     newPendingRequests(),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	size, bp := cfg.ChannelBuffer, cfg.Backpressure
+	c.acks = newOutbox[protocol.Ack](c, size, bp.Acks)
+	c.trades = newOutbox[protocol.Trade](c, size, bp.Trades)
+	c.bookUpdates = newOutbox[protocol.BookUpdate](c, size, bp.BookUpdates)
+	c.cancelAcks = newOutbox[protocol.CancelAck](c, size, bp.CancelAcks)
+	c.rejects = newOutbox[protocol.Reject](c, size, bp.Rejects)
+	c.unknown = newOutbox[protocol.RawMessage](c, size, bp.Unknown)
+	c.events = newOutbox[Event](c, size, bp.Events)
+
+	return c, nil
 }
 
 // Connect establishes a connection to the server and starts background goroutines.
@@ -152,15 +360,11 @@ func (c *Client) Connect() error {
 		return ErrClientClosed
 	}
 
-	// Create transport
-	c.transport = transport.New(&c.cfg)
-
-	if err := c.transport.Connect(); err != nil {
+	t := transport.New(&c.cfg)
+	if err := t.Connect(); err != nil {
 		return err
 	}
-
-	// Create encoder
-	c.encoder = protocol.NewEncoder(c.transport.Writer())
+	c.setTransport(t)
 
 	c.wg.Add(2)
 	go c.readLoop()
@@ -173,8 +377,8 @@ func (c *Client) Connect() error {
 func (c *Client) Close() error {
 	c.cancel()
 
-	if c.transport != nil {
-		_ = c.transport.Close()
+	if t := c.currentTransport(); t != nil {
+		_ = t.Close()
 	}
 
 	c.wg.Wait()
@@ -184,68 +388,368 @@ func (c *Client) Close() error {
 }
 
 func (c *Client) closeChannels() {
-	close(c.ackCh)
-	close(c.tradeCh)
-	close(c.bookUpdateCh)
-	close(c.cancelAckCh)
+	close(c.acks.ch)
+	close(c.trades.ch)
+	close(c.bookUpdates.ch)
+	close(c.cancelAcks.ch)
+	close(c.rejects.ch)
+	close(c.unknown.ch)
+	close(c.events.ch)
 	close(c.errorCh)
 	close(c.reconnectCh)
-}
 
-// SendOrder sends a new order to the matching engine.
-func (c *Client) SendOrder(order protocol.NewOrder) error {
-	if err := protocol.ValidateOrder(&order); err != nil {
-		return err
+	if c.orders != nil {
+		c.orders.Close()
+	}
+	if c.book != nil {
+		c.book.Close()
 	}
+}
+
+// SendOrder sends a new order to the matching engine and returns its
+// order ID. A zero OrderID is filled in from the client's allocator.
+// If the write queue is full it returns ErrWriteQueueFull, or blocks
+// until there is room when Config.BlockingSends is set.
+func (c *Client) SendOrder(order protocol.NewOrder) (uint32, error) {
+	return c.sendOrder(context.Background(), c.cfg.BlockingSends, order)
+}
 
-	return c.enqueueWrite(writeRequest{reqType: writeRequestOrder, order: order})
+// SendOrderContext sends a new order, blocking while the write queue is
+// full until there is room or ctx ends. It returns the order ID, as for
+// SendOrder.
+func (c *Client) SendOrderContext(ctx context.Context, order protocol.NewOrder) (uint32, error) {
+	return c.sendOrder(ctx, true, order)
 }
 
 // SendCancel sends a cancel request to the matching engine.
+// Queue-full behavior follows Config.BlockingSends, as for SendOrder.
 func (c *Client) SendCancel(cancel protocol.CancelOrder) error {
-	if err := protocol.ValidateCancel(&cancel); err != nil {
-		return err
-	}
+	return c.sendCancel(context.Background(), c.cfg.BlockingSends, writeRequest{cancel: cancel})
+}
 
-	return c.enqueueWrite(writeRequest{reqType: writeRequestCancel, cancel: cancel})
+// SendCancelContext sends a cancel request, blocking while the write
+// queue is full until there is room or ctx ends.
+func (c *Client) SendCancelContext(ctx context.Context, cancel protocol.CancelOrder) error {
+	return c.sendCancel(ctx, true, writeRequest{cancel: cancel})
 }
 
 // SendFlush sends a flush command to clear all order books.
+// Queue-full behavior follows Config.BlockingSends, as for SendOrder.
 func (c *Client) SendFlush() error {
-	return c.enqueueWrite(writeRequest{reqType: writeRequestFlush})
+	return c.enqueueWrite(context.Background(), c.cfg.BlockingSends, writeRequest{reqType: writeRequestFlush})
+}
+
+// SendFlushContext sends a flush command, blocking while the write queue
+// is full until there is room or ctx ends.
+func (c *Client) SendFlushContext(ctx context.Context) error {
+	return c.enqueueWrite(ctx, true, writeRequest{reqType: writeRequestFlush})
+}
+
+func (c *Client) sendOrder(ctx context.Context, block bool, order protocol.NewOrder) (uint32, error) {
+	if atomic.LoadInt32(&c.halted) != 0 {
+		return 0, ErrTradingHalted
+	}
+
+	if err := protocol.ValidateOrder(&order); err != nil {
+		return 0, err
+	}
+
+	if err := c.assignOrderID(&order); err != nil {
+		return 0, err
+	}
+
+	if c.risk != nil {
+		if err := c.risk.Admit(order); err != nil {
+			var riskErr *risk.Error
+			if errors.As(err, &riskErr) {
+				c.stats.IncRiskRejects()
+			}
+			return 0, err
+		}
+	}
+
+	if c.orders != nil {
+		if err := c.orders.Track(order); err != nil {
+			c.releaseRisk(order)
+			return 0, err
+		}
+	}
+
+	// Before sending, so a fill cannot arrive ahead of the user
+	if c.positions != nil {
+		c.positions.AddUser(order.UserID)
+	}
+
+	if err := c.enqueueWrite(ctx, block, writeRequest{reqType: writeRequestOrder, order: order}); err != nil {
+		if c.orders != nil {
+			c.orders.Untrack(order.UserID, order.OrderID)
+		}
+		c.releaseRisk(order)
+		return 0, err
+	}
+	return order.OrderID, nil
+}
+
+// assignOrderID fills in a zero OrderID from the allocator. An ID taken
+// for an order that then fails to send is not reused.
+func (c *Client) assignOrderID(order *protocol.NewOrder) error {
+	if order.OrderID != 0 {
+		return nil
+	}
+	id, err := c.orderIDs.Next()
+	if err != nil {
+		return err
+	}
+	order.OrderID = id
+	return nil
+}
+
+func (c *Client) releaseRisk(order protocol.NewOrder) {
+	if c.risk != nil {
+		c.risk.Release(order.UserID, order.OrderID)
+	}
+}
+
+func (c *Client) sendCancel(ctx context.Context, block bool, req writeRequest) error {
+	if err := protocol.ValidateCancel(&req.cancel); err != nil {
+		return err
+	}
+
+	// Marked first so a reject racing the enqueue is still read as the
+	// cancel's, and unmarked if the cancel is never queued
+	marked := c.orders != nil && c.orders.CancelSent(req.cancel.UserID, req.cancel.OrderID)
+
+	req.reqType = writeRequestCancel
+	err := c.enqueueWrite(ctx, block, req)
+	if err != nil && marked {
+		c.orders.CancelUnsent(req.cancel.UserID, req.cancel.OrderID)
+	}
+	return err
 }
 
-func (c *Client) enqueueWrite(req writeRequest) error {
+// enqueueWrite queues req for the write loop, first waiting for the rate
+// limiter if one is configured. When the queue is full it fails with
+// ErrWriteQueueFull, or if block is set waits for room until ctx ends or
+// the client closes.
+func (c *Client) enqueueWrite(ctx context.Context, block bool, req writeRequest) error {
 	if c.ctx.Err() != nil {
 		return ErrClientClosed
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if c.limiter != nil {
+		if err := c.waitRate(ctx, &req); err != nil {
+			return err
+		}
+	}
+
+	if c.latency != nil && req.reqType == writeRequestOrder {
+		req.enqueuedAt = time.Now()
+	}
+
+	err := c.queueWrite(ctx, block, req)
+	if err != nil && c.limiter != nil {
+		c.limiter.Unreserve(req.rateClass())
+	}
+	return err
+}
 
+func (c *Client) queueWrite(ctx context.Context, block bool, req writeRequest) error {
 	select {
 	case <-c.ctx.Done():
 		return ErrClientClosed
 	case c.writeCh <- req:
-		c.stats.IncMessagesSent()
+		c.countSent(&req)
 		return nil
 	default:
+	}
+
+	if !block {
 		c.stats.IncDroppedMessages()
+		c.countSymbol(req.symbol(), stats.SymbolDropped)
 		return ErrWriteQueueFull
 	}
+
+	select {
+	case <-c.ctx.Done():
+		return ErrClientClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	case c.writeCh <- req:
+		c.countSent(&req)
+		return nil
+	}
+}
+
+// countSent records a request queued for sending.
+func (c *Client) countSent(req *writeRequest) {
+	c.stats.IncMessagesSent()
+	switch req.reqType {
+	case writeRequestOrder:
+		c.stats.IncNewOrdersSent()
+		c.countSymbol(req.order.Symbol, stats.SymbolNewOrdersSent)
+	case writeRequestCancel:
+		c.stats.IncCancelsSent()
+		c.countSymbol(req.cancel.Symbol, stats.SymbolCancelsSent)
+	case writeRequestFlush:
+		c.stats.IncFlushesSent()
+	}
+}
+
+// countReceived records a decoded message.
+func (c *Client) countReceived(msg *protocol.Message) {
+	c.stats.IncMessagesReceived()
+	switch {
+	case msg.Ack != nil:
+		c.stats.IncAcksReceived()
+		c.countSymbol(msg.Ack.Symbol, stats.SymbolAcks)
+	case msg.Trade != nil:
+		c.stats.IncTradesReceived()
+		c.countSymbol(msg.Trade.Symbol, stats.SymbolTrades)
+	case msg.BookUpdate != nil:
+		c.stats.IncBookUpdatesReceived()
+		c.countSymbol(msg.BookUpdate.Symbol, stats.SymbolBookUpdates)
+	case msg.CancelAck != nil:
+		c.stats.IncCancelAcksReceived()
+		c.countSymbol(msg.CancelAck.Symbol, stats.SymbolCancelAcks)
+	case msg.Reject != nil:
+		c.stats.IncRejectsReceived()
+		c.countSymbol(msg.Reject.Symbol, stats.SymbolRejects)
+	}
+}
+
+// countSymbol increments a per-symbol counter if Config.TrackSymbolStats
+// is on.
+func (c *Client) countSymbol(symbol string, counter stats.SymbolCounter) {
+	if c.cfg.TrackSymbolStats && symbol != "" {
+		c.stats.IncSymbol(symbol, counter)
+	}
+}
+
+// waitRate takes rate limiter tokens for req, sleeping until they are
+// available unless ctx ends or the client closes first. Under
+// RateLimitReject it fails instead of sleeping unless req.mustSend is set.
+func (c *Client) waitRate(ctx context.Context, req *writeRequest) error {
+	class, userID := req.rateClass()
+
+	var delay time.Duration
+	if req.mustSend {
+		delay = c.limiter.ReserveWait(class, userID)
+	} else {
+		var err error
+		if delay, err = c.limiter.Reserve(class, userID); err != nil {
+			c.stats.IncRateLimited()
+			return err
+		}
+	}
+	if delay <= 0 {
+		return nil
+	}
+	c.stats.IncRateDelayed()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		c.limiter.Unreserve(class, userID)
+		return ctx.Err()
+	case <-c.ctx.Done():
+		c.limiter.Unreserve(class, userID)
+		return ErrClientClosed
+	}
 }
 
 // Channel accessors
-func (c *Client) Acks() <-chan protocol.Ack               { return c.ackCh }
-func (c *Client) Trades() <-chan protocol.Trade           { return c.tradeCh }
-func (c *Client) BookUpdates() <-chan protocol.BookUpdate { return c.bookUpdateCh }
-func (c *Client) CancelAcks() <-chan protocol.CancelAck   { return c.cancelAckCh }
-func (c *Client) Errors() <-chan error                    { return c.errorCh }
+func (c *Client) Acks() <-chan protocol.Ack                  { return c.acks.ch }
+func (c *Client) Trades() <-chan protocol.Trade              { return c.trades.ch }
+func (c *Client) BookUpdates() <-chan protocol.BookUpdate    { return c.bookUpdates.ch }
+func (c *Client) CancelAcks() <-chan protocol.CancelAck      { return c.cancelAcks.ch }
+func (c *Client) Rejects() <-chan protocol.Reject            { return c.rejects.ch }
+func (c *Client) Unknown() <-chan protocol.RawMessage        { return c.unknown.ch }
+func (c *Client) Events() <-chan Event                       { return c.events.ch }
+func (c *Client) Errors() <-chan error                       { return c.errorCh }
 func (c *Client) Reconnects() <-chan protocol.ReconnectEvent { return c.reconnectCh }
 
+// DeliversEvents returns true if messages are delivered on Events(),
+// which needs DeliverEvents or DeliverBoth and no Handler.
+func (c *Client) DeliversEvents() bool {
+	return c.cfg.Handler == nil && c.cfg.UsesEvents()
+}
+
 // IsConnected returns true if the client is currently connected.
 func (c *Client) IsConnected() bool {
-	if c.transport == nil {
-		return false
+	t := c.currentTransport()
+	return t != nil && t.IsConnected()
+}
+
+// Protocol returns the wire protocol in use for this session.
+// With ProtocolAuto over TCP it returns ProtocolAuto until the first
+// inbound frame has been seen, then the detected protocol. Outbound
+// requests are held until then; see Config.Protocol.
+// It is not meaningful when Config.Codec selects a custom codec.
+func (c *Client) Protocol() Protocol {
+	return config.Protocol(atomic.LoadInt32(&c.negotiated))
+}
+
+// Codec returns the name of the codec in use, or "" while protocol
+// auto-detection is still pending.
+func (c *Client) Codec() string {
+	if codec := c.currentCodec(); codec != nil {
+		return codec.Name()
 	}
-	return c.transport.IsConnected()
+	return ""
+}
+
+// Orders returns the order manager, or nil if Config.TrackOrders is off.
+func (c *Client) Orders() *orders.Manager {
+	return c.orders
+}
+
+// Book returns the best bid/offer book, or nil if Config.TrackBook is off.
+func (c *Client) Book() *book.Book {
+	return c.book
+}
+
+// Positions returns the position keeper, or nil if Config.TrackPositions
+// is off. User IDs of orders sent through the client are added to it
+// automatically; call AddUser for orders placed elsewhere.
+func (c *Client) Positions() *position.Keeper {
+	return c.positions
+}
+
+// Latency returns the tracker of orders awaiting their first response,
+// or nil if Config.TrackLatency is off. The latency histograms themselves
+// are in Stats.
+func (c *Client) Latency() *latency.Tracker {
+	return c.latency
+}
+
+// LatencyInterval returns the enqueue→wire and wire→ack latency recorded
+// since the previous call and starts a new interval. The session totals
+// in Stats are unaffected.
+func (c *Client) LatencyInterval() (queue, ack LatencySnapshot) {
+	return c.stats.LatencyInterval()
+}
+
+// Risk returns the pre-trade risk checker, or nil if Config.Risk is nil.
+func (c *Client) Risk() *risk.Checker {
+	return c.risk
+}
+
+// OrderIDs returns the allocator that fills in zero order IDs.
+func (c *Client) OrderIDs() orderid.Allocator {
+	return c.orderIDs
+}
+
+// RateLimiter returns the outbound rate limiter, or nil if
+// Config.RateLimit is nil.
+func (c *Client) RateLimiter() *ratelimit.Limiter {
+	return c.limiter
 }
 
 // Stats returns a snapshot of the current client statistics.
@@ -253,6 +757,152 @@ func (c *Client) Stats() stats.Snapshot {
 	return c.stats.GetSnapshot()
 }
 
+// currentCodec returns the configured codec, or the built-in codec for the
+// negotiated protocol. It returns nil while auto-detection is pending.
+func (c *Client) currentCodec() protocol.Codec {
+	if c.codec != nil {
+		return c.codec
+	}
+
+	var name string
+	switch c.Protocol() {
+	case config.ProtocolCSV:
+		name = protocol.CodecCSV
+	case config.ProtocolBinary:
+		name = protocol.CodecBinary
+	default:
+		return nil
+	}
+
+	codec, _ := protocol.LookupCodec(name)
+	return codec
+}
+
+// currentTransport returns the transport of the current connection.
+func (c *Client) currentTransport() transport.Transport {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	return c.transport
+}
+
+// setTransport installs a newly connected transport and its encoder.
+func (c *Client) setTransport(t transport.Transport) {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	c.transport = t
+	c.resetEncoder()
+}
+
+// resetEncoder creates the encoder for the current codec. The caller
+// holds connMu. Until auto-detection completes, outbound messages are
+// sent as CSV.
+func (c *Client) resetEncoder() {
+	codec := c.currentCodec()
+	if codec == nil {
+		codec, _ = protocol.LookupCodec(protocol.CodecCSV)
+	}
+
+	c.encoder = codec.NewEncoder(&meteredWriter{w: c.transport.Writer(), stats: &c.stats})
+	c.encoderProtocol = c.Protocol()
+}
+
+// newDecoder creates the decoder for the current codec, or an
+// auto-detecting decoder while detection is pending.
+func (c *Client) newDecoder(r io.Reader) protocol.MessageDecoder {
+	if codec := c.currentCodec(); codec != nil {
+		return codec.NewDecoder(r)
+	}
+	return protocol.NewAutoDecoder(r)
+}
+
+// lockProtocol records the protocol detected by an auto decoder.
+func (c *Client) lockProtocol(decoder protocol.MessageDecoder) {
+	auto, ok := decoder.(*protocol.AutoDecoder)
+	if !ok {
+		return
+	}
+
+	var proto config.Protocol
+	switch auto.Format() {
+	case protocol.FormatBinary:
+		proto = config.ProtocolBinary
+	case protocol.FormatCSV:
+		proto = config.ProtocolCSV
+	default:
+		return
+	}
+
+	if atomic.CompareAndSwapInt32(&c.negotiated, int32(config.ProtocolAuto), int32(proto)) {
+		close(c.detected)
+	}
+}
+
+// probeUserID is the user of every detection probe. Each probe cancels
+// its own order ID, counting from 1, so a reply is matched to a probe
+// by both IDs rather than mistaken for one to a real request.
+const probeUserID = math.MaxUint32
+
+// awaitDetection holds outbound requests until auto-detection locks the
+// protocol. The engine only replies to requests, so it probes with a
+// cancel for probeUserID, which names no live order, alternating CSV and
+// binary until a reply arrives. If none does, requests are sent as CSV.
+func (c *Client) awaitDetection() {
+	for i := 0; i < config.MaxDetectProbes; i++ {
+		name := protocol.CodecCSV
+		if i%2 == 1 {
+			name = protocol.CodecBinary
+		}
+		c.sendProbe(name)
+
+		select {
+		case <-c.detected:
+			return
+		case <-c.ctx.Done():
+			return
+		case <-time.After(config.DetectProbeInterval):
+		}
+	}
+
+	c.sendError(ErrProtocolUndetected)
+}
+
+// sendProbe writes one detection probe with the named codec.
+func (c *Client) sendProbe(name string) {
+	codec, _ := protocol.LookupCodec(name)
+
+	t := c.currentTransport()
+	if t == nil || !t.IsConnected() {
+		return
+	}
+	enc := codec.NewEncoder(&meteredWriter{w: t.Writer(), stats: &c.stats})
+
+	probe := protocol.CancelOrder{UserID: probeUserID, OrderID: atomic.AddUint32(&c.probes, 1)}
+	err := enc.EncodeCancel(&probe)
+	if ft, ok := t.(FlushableTransport); ok && err == nil {
+		err = ft.Flush()
+	}
+	if err != nil {
+		c.writeFailed(err)
+	}
+}
+
+// isProbeReply reports whether msg answers a detection probe. Probe
+// replies are not delivered. A server error names no order, so it is
+// never taken for one.
+func (c *Client) isProbeReply(msg *protocol.Message) bool {
+	var userID, orderID uint32
+	switch {
+	case msg.CancelAck != nil:
+		userID, orderID = msg.CancelAck.UserID, msg.CancelAck.OrderID
+	case msg.Reject != nil:
+		userID, orderID = msg.Reject.UserID, msg.Reject.OrderID
+	default:
+		return false
+	}
+	return userID == probeUserID && orderID != 0 && orderID <= atomic.LoadUint32(&c.probes)
+}
+
 // readLoop continuously reads messages from the server.
 func (c *Client) readLoop() {
 	defer c.wg.Done()
@@ -269,7 +919,7 @@ func (c *Client) readLoop() {
 			return
 		}
 
-		if c.transport == nil || !c.transport.IsConnected() {
+		if !c.IsConnected() {
 			if !c.waitForReconnect() {
 				return
 			}
@@ -288,14 +938,17 @@ func (c *Client) readLoop() {
 }
 
 func (c *Client) processInboundMessages() error {
-	reader := c.transport.Reader()
+	reader := c.currentTransport().Reader()
 	if reader == nil {
 		return errors.New("no reader available")
 	}
 
-	decoder := protocol.NewDecoder(reader)
+	decoder := c.newDecoder(&meteredReader{r: reader, stats: &c.stats})
 	batchCount := 0
 
+	// Reused across frames; dispatch copies values out before the next decode
+	var msg protocol.Message
+
 	for {
 		if batchCount >= config.MaxMessageBatchSize {
 			if c.ctx.Err() != nil {
@@ -304,21 +957,56 @@ func (c *Client) processInboundMessages() error {
 			batchCount = 0
 		}
 
-		msg, err := decoder.Decode()
+		err := protocol.DecodeInto(decoder, &msg)
+		if c.Protocol() == config.ProtocolAuto {
+			c.lockProtocol(decoder)
+		}
 		if err != nil {
 			if err == io.EOF {
 				return errors.New("connection closed by server")
 			}
+			if errors.Is(err, transport.ErrMalformedDatagram) {
+				// The whole datagram was dropped; the next read starts clean
+				c.sendError(err)
+				c.stats.IncErrorCount()
+				continue
+			}
+			var unknown *protocol.UnknownMessageError
+			if errors.As(err, &unknown) && c.cfg.UnknownMessages != config.UnknownStrict {
+				// The frame was consumed whole, so the stream is still aligned
+				c.handleUnknown(unknown)
+				batchCount++
+				continue
+			}
 			c.sendError(fmt.Errorf("decode error: %w", err))
 			return err
 		}
 
-		c.dispatchMessage(msg)
-		c.stats.IncMessagesReceived()
+		c.dispatchMessage(&msg)
+		c.countReceived(&msg)
 		batchCount++
 	}
 }
 
+// handleUnknown applies the configured policy to an unrecognized frame.
+func (c *Client) handleUnknown(unknown *protocol.UnknownMessageError) {
+	c.stats.IncUnknownMessages()
+
+	switch c.cfg.UnknownMessages {
+	case config.UnknownSkip:
+		c.sendError(unknown)
+	case config.UnknownPassThrough:
+		if c.cfg.UsesEvents() {
+			ev := c.nextEvent(EventRaw)
+			ev.Raw = unknown.Raw
+			c.events.deliver(ev)
+		}
+		if c.cfg.UsesChannels() {
+			c.unknown.deliver(unknown.Raw)
+		}
+	}
+}
+
 func (c *Client) handleReadError(err error) bool {
 	if c.ctx.Err() != nil {
 		return false
@@ -327,62 +1015,180 @@ func (c *Client) handleReadError(err error) bool {
 	c.sendError(fmt.Errorf("read error: %w", err))
 	c.stats.IncErrorCount()
 
+	stale := c.markOrdersUnknown()
+	if c.latency != nil {
+		c.latency.ClearPending()
+	}
+
 	if c.cfg.AutoReconnect {
-		return c.reconnect()
+		return c.reconnect(stale)
 	}
 	return false
 }
 
 func (c *Client) dispatchMessage(msg *protocol.Message) {
+	if c.isProbeReply(msg) {
+		return
+	}
+	if c.orders != nil {
+		c.trackMessage(msg)
+	}
+	if c.book != nil && msg.BookUpdate != nil {
+		c.book.Apply(*msg.BookUpdate)
+	}
+	if c.positions != nil {
+		c.applyPositions(msg)
+	}
+	if c.latency != nil {
+		c.applyLatency(msg)
+	}
+	if c.risk != nil {
+		c.applyRisk(msg)
+	}
+	c.pending.resolve(msg)
+
+	if h := c.cfg.Handler; h != nil {
+		handleMessage(h, msg)
+		return
+	}
+
+	if c.cfg.UsesEvents() {
+		if ev := c.messageEvent(msg); ev.Kind != EventNone {
+			c.events.deliver(ev)
+		}
+	}
+	if !c.cfg.UsesChannels() {
+		return
+	}
+
 	switch {
 	case msg.Ack != nil:
-		c.trySendAck(*msg.Ack)
+		c.acks.deliver(*msg.Ack)
 	case msg.Trade != nil:
-		c.trySendTrade(*msg.Trade)
+		c.trades.deliver(*msg.Trade)
 	case msg.BookUpdate != nil:
-		c.trySendBookUpdate(*msg.BookUpdate)
+		c.bookUpdates.deliver(*msg.BookUpdate)
 	case msg.CancelAck != nil:
-		c.trySendCancelAck(*msg.CancelAck)
+		c.cancelAcks.deliver(*msg.CancelAck)
+	case msg.Reject != nil:
+		c.rejects.deliver(*msg.Reject)
 	}
 }
 
-func (c *Client) trySendAck(v protocol.Ack) {
-	select {
-	case c.ackCh <- v:
-	default:
-		c.stats.IncDroppedMessages()
-		c.sendError(ErrChannelFull)
+// handleMessage invokes the handler callback for msg.
+func handleMessage(h protocol.Handler, msg *protocol.Message) {
+	switch {
+	case msg.Ack != nil:
+		h.OnAck(*msg.Ack)
+	case msg.Trade != nil:
+		h.OnTrade(*msg.Trade)
+	case msg.BookUpdate != nil:
+		h.OnBookUpdate(*msg.BookUpdate)
+	case msg.CancelAck != nil:
+		h.OnCancelAck(*msg.CancelAck)
+	case msg.Reject != nil:
+		h.OnReject(*msg.Reject)
 	}
 }
 
-func (c *Client) trySendTrade(v protocol.Trade) {
-	select {
-	case c.tradeCh <- v:
-	default:
-		c.stats.IncDroppedMessages()
-		c.sendError(ErrChannelFull)
+// markOrdersUnknown marks open tracked orders as unknown after the
+// connection drops and returns them, unless the policy keeps them.
+func (c *Client) markOrdersUnknown() []orders.Order {
+	if c.orders == nil || c.cfg.OnDisconnect == config.DisconnectKeep {
+		return nil
 	}
+	return c.orders.MarkUnknown()
 }
 
-func (c *Client) trySendBookUpdate(v protocol.BookUpdate) {
-	select {
-	case c.bookUpdateCh <- v:
-	default:
-		c.stats.IncDroppedMessages()
-		c.sendError(ErrChannelFull)
+// cancelStale queues a cancel for each order left open by a disconnect
+// and returns how many were queued. It waits for write queue space and
+// for the rate limiter, even under RateLimitReject, since losing one of
+// these cancels would leave an order unmanaged.
+func (c *Client) cancelStale(stale []orders.Order) int {
+	sent := 0
+	for i := range stale {
+		o := &stale[i]
+		err := c.sendCancel(c.ctx, true, writeRequest{mustSend: true, cancel: protocol.CancelOrder{
+			Symbol:  o.Symbol,
+			UserID:  o.UserID,
+			OrderID: o.OrderID,
+		}})
+		if err != nil {
+			c.sendError(fmt.Errorf("cancel-on-disconnect user=%d order=%d: %w", o.UserID, o.OrderID, err))
+			continue
+		}
+		sent++
 	}
+	return sent
 }
 
-func (c *Client) trySendCancelAck(v protocol.CancelAck) {
-	select {
-	case c.cancelAckCh <- v:
-	default:
-		c.stats.IncDroppedMessages()
-		c.sendError(ErrChannelFull)
+// trackMessage applies an inbound message to the order manager.
+func (c *Client) trackMessage(msg *protocol.Message) {
+	switch {
+	case msg.Ack != nil:
+		c.orders.ApplyAck(*msg.Ack)
+	case msg.Trade != nil:
+		c.orders.ApplyTrade(*msg.Trade)
+	case msg.CancelAck != nil:
+		c.orders.ApplyCancelAck(*msg.CancelAck)
+	case msg.Reject != nil:
+		c.orders.ApplyReject(*msg.Reject)
+	}
+}
+
+// applyPositions feeds fills and marks to the position keeper.
+func (c *Client) applyPositions(msg *protocol.Message) {
+	switch {
+	case msg.Trade != nil:
+		c.positions.ApplyTrade(*msg.Trade)
+	case msg.BookUpdate != nil:
+		c.positions.ApplyBookUpdate(*msg.BookUpdate)
+	}
+}
+
+// applyLatency records the wire→ack latency of the order a response
+// names. A trade names two orders; either may be ours.
+func (c *Client) applyLatency(msg *protocol.Message) {
+	now := time.Now()
+	switch {
+	case msg.Ack != nil:
+		c.recordResponded(msg.Ack.UserID, msg.Ack.OrderID, now)
+	case msg.Trade != nil:
+		c.recordResponded(msg.Trade.BuyUserID, msg.Trade.BuyOrderID, now)
+		c.recordResponded(msg.Trade.SellUserID, msg.Trade.SellOrderID, now)
+	case msg.CancelAck != nil:
+		c.recordResponded(msg.CancelAck.UserID, msg.CancelAck.OrderID, now)
+	case msg.Reject != nil:
+		c.latency.Forget(msg.Reject.UserID, msg.Reject.OrderID)
+	}
+}
+
+func (c *Client) recordResponded(userID, orderID uint32, at time.Time) {
+	if d, ok := c.latency.Responded(userID, orderID, at); ok {
+		c.stats.RecordAckLatency(d)
+	}
+}
+
+// applyRisk updates the risk checker's open orders, positions and prices.
+func (c *Client) applyRisk(msg *protocol.Message) {
+	switch {
+	case msg.Ack != nil:
+		c.risk.ApplyAck(*msg.Ack)
+	case msg.Trade != nil:
+		c.risk.ApplyTrade(*msg.Trade)
+	case msg.CancelAck != nil:
+		c.risk.ApplyCancelAck(*msg.CancelAck)
+	case msg.Reject != nil:
+		c.risk.ApplyReject(*msg.Reject)
 	}
 }
 
 func (c *Client) sendError(err error) {
+	if h := c.cfg.Handler; h != nil {
+		h.OnError(err)
+		return
+	}
+
 	select {
 	case c.errorCh <- err:
 	default:
@@ -393,49 +1199,142 @@ func (c *Client) sendError(err error) {
 func (c *Client) writeLoop() {
 	defer c.wg.Done()
 
+	if c.codec == nil && c.Protocol() == config.ProtocolAuto {
+		c.awaitDetection()
+	}
+
+	batch := make([]writeRequest, 0, c.cfg.WriteBatchSize)
+
 	for {
 		select {
 		case <-c.ctx.Done():
 			return
 		case req := <-c.writeCh:
-			if err := c.processWrite(req); err != nil {
-				c.sendError(fmt.Errorf("write error: %w", err))
-				c.stats.IncErrorCount()
-			}
+			batch = c.fillBatch(append(batch[:0], req))
+			c.processBatch(batch)
 		}
 	}
 }
 
-func (c *Client) processWrite(req writeRequest) error {
-	if c.encoder == nil {
-		return ErrNotConnected
+// fillBatch appends queued requests to batch until it reaches
+// WriteBatchSize or the queue is empty. With WriteLinger set it waits up
+// to that long for more requests before giving up on a partial batch.
+func (c *Client) fillBatch(batch []writeRequest) []writeRequest {
+	var linger *time.Timer
+
+	for len(batch) < c.cfg.WriteBatchSize {
+		select {
+		case req := <-c.writeCh:
+			batch = append(batch, req)
+			continue
+		default:
+		}
+
+		if c.cfg.WriteLinger <= 0 {
+			break
+		}
+		if linger == nil {
+			linger = time.NewTimer(c.cfg.WriteLinger)
+			defer linger.Stop()
+		}
+
+		select {
+		case req := <-c.writeCh:
+			batch = append(batch, req)
+		case <-linger.C:
+			return batch
+		case <-c.ctx.Done():
+			return batch
+		}
 	}
 
-	var err error
+	return batch
+}
 
-	switch req.reqType {
-	case writeRequestOrder:
-		err = c.encoder.EncodeNewOrder(&req.order)
-	case writeRequestCancel:
-		err = c.encoder.EncodeCancel(&req.cancel)
-	case writeRequestFlush:
-		err = c.encoder.EncodeFlush()
+// processBatch encodes the batch contiguously and flushes the transport
+// once. A failed request is reported and the rest are still written.
+func (c *Client) processBatch(batch []writeRequest) {
+	t, enc := c.writeTarget()
+	if enc == nil {
+		for range batch {
+			c.writeFailed(ErrNotConnected)
+		}
+		return
 	}
 
-	if err != nil {
-		return err
+	for i := range batch {
+		if err := encodeRequest(enc, &batch[i]); err != nil {
+			c.writeFailed(err)
+			batch[i].enqueuedAt = time.Time{}
+		}
 	}
 
 	// Flush the transport if it supports it
-	if ft, ok := c.transport.(FlushableTransport); ok {
-		return ft.Flush()
+	flushed := true
+	if ft, ok := t.(FlushableTransport); ok {
+		if err := ft.Flush(); err != nil {
+			c.writeFailed(err)
+			flushed = false
+		}
+	}
+	c.stats.IncWriteBatches()
+
+	if c.latency != nil && flushed {
+		c.recordWritten(batch)
 	}
+}
+
+// recordWritten records the enqueue→wire latency of the batch's orders
+// and starts timing them from the wire to their response.
+func (c *Client) recordWritten(batch []writeRequest) {
+	now := time.Now()
+	for i := range batch {
+		req := &batch[i]
+		if req.reqType == writeRequestOrder && !req.enqueuedAt.IsZero() {
+			c.stats.RecordQueueLatency(now.Sub(req.enqueuedAt))
+			c.latency.Written(req.order.UserID, req.order.OrderID, now)
+		}
+	}
+}
 
+// writeTarget returns the transport and encoder to write the next batch
+// with. Only the write loop encodes, so the encoder is used without
+// connMu; a reconnect installs a new one rather than changing it.
+func (c *Client) writeTarget() (transport.Transport, protocol.MessageEncoder) {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	if c.encoder == nil {
+		return nil, nil
+	}
+
+	// Switch encoders once auto-detection has locked the protocol
+	if c.encoderProtocol != c.Protocol() {
+		c.resetEncoder()
+	}
+	return c.transport, c.encoder
+}
+
+func encodeRequest(enc protocol.MessageEncoder, req *writeRequest) error {
+	switch req.reqType {
+	case writeRequestOrder:
+		return enc.EncodeNewOrder(&req.order)
+	case writeRequestCancel:
+		return enc.EncodeCancel(&req.cancel)
+	case writeRequestFlush:
+		return enc.EncodeFlush()
+	}
 	return nil
 }
 
-// reconnect attempts to reconnect with exponential backoff.
-func (c *Client) reconnect() bool {
+func (c *Client) writeFailed(err error) {
+	c.sendError(fmt.Errorf("write error: %w", err))
+	c.stats.IncErrorCount()
+}
+
+// reconnect attempts to reconnect with exponential backoff. stale holds
+// the orders that were open when the connection dropped.
+func (c *Client) reconnect(stale []orders.Order) bool {
 	delay := c.cfg.ReconnectMinDelay
 
 	for attempt := 1; attempt <= config.MaxReconnectAttempts; attempt++ {
@@ -445,15 +1344,13 @@ func (c *Client) reconnect() bool {
 		case <-time.After(delay):
 		}
 
-		// Close existing transport
-		if c.transport != nil {
-			_ = c.transport.Close()
+		// Close the existing transport; a write blocked on it fails
+		if t := c.currentTransport(); t != nil {
+			_ = t.Close()
 		}
 
-		// Create new transport
-		c.transport = transport.New(&c.cfg)
-
-		if err := c.transport.Connect(); err != nil {
+		t := transport.New(&c.cfg)
+		if err := t.Connect(); err != nil {
 			c.sendError(fmt.Errorf("reconnect attempt %d failed: %w", attempt, err))
 
 			delay *= 2
@@ -462,15 +1359,22 @@ func (c *Client) reconnect() bool {
 			}
 			continue
 		}
-
-		// Recreate encoder
-		c.encoder = protocol.NewEncoder(c.transport.Writer())
+		c.setTransport(t)
 
 		c.stats.IncReconnectCount()
 
-		select {
-		case c.reconnectCh <- protocol.ReconnectEvent{Attempt: attempt}:
-		default:
+		event := protocol.ReconnectEvent{Attempt: attempt, UnknownOrders: len(stale)}
+		if c.cfg.OnDisconnect == config.DisconnectCancel {
+			event.CancelsSent = c.cancelStale(stale)
+		}
+
+		if h := c.cfg.Handler; h != nil {
+			h.OnReconnect(event)
+		} else {
+			select {
+			case c.reconnectCh <- event:
+			default:
+			}
 		}
 
 		return true
@@ -490,7 +1394,7 @@ func (c *Client) waitForReconnect() bool {
 		case <-c.ctx.Done():
 			return false
 		case <-ticker.C:
-			if c.transport != nil && c.transport.IsConnected() {
+			if c.IsConnected() {
 				return true
 			}
 		}
@@ -3,9 +3,19 @@
 package meclient
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/orders"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
 )
 
 func TestClient_New_Valid(t *testing.T) {
@@ -64,6 +74,55 @@ func TestClient_ConnectAndClose(t *testing.T) {
 	}
 }
 
+func TestClient_Close_StalledPeer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	defer listener.Close()
+
+	// Accept but never read, so the client's writes block once the
+	// socket buffers fill
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+	defer func() {
+		select {
+		case conn := <-accepted:
+			conn.Close()
+		default:
+		}
+	}()
+
+	client := newTestClient(t, listener.Addr().String(), nil)
+	mustConnect(t, client)
+
+	order := NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 50, Side: SideBuy}
+	for i := 0; i < 200000; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		_, err := client.SendOrderContext(ctx, order)
+		cancel()
+		if err != nil {
+			break // The queue stopped draining: the write loop is blocked
+		}
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		client.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Close blocked behind a stalled write")
+	}
+}
+
 func TestClient_SendOrder_WithoutConnect(t *testing.T) {
 	cfg := DefaultConfig("localhost:1234")
 	client, _ := New(cfg)
@@ -79,7 +138,7 @@ func TestClient_SendOrder_WithoutConnect(t *testing.T) {
 
 	// SendOrder queues to channel, doesn't require connection
 	// It will return ErrClientClosed if client is closed, or succeed if channel has space
-	err := client.SendOrder(order)
+	_, err := client.SendOrder(order)
 	
 	// The send succeeds because it just queues to the write channel
 	// The actual send happens in the write loop (which isn't running)
@@ -109,7 +168,7 @@ func TestClient_SendOrder_AfterClose(t *testing.T) {
 		OrderID: 1,
 	}
 
-	err := client.SendOrder(order)
+	_, err := client.SendOrder(order)
 	if err != ErrClientClosed {
 		t.Errorf("expected ErrClientClosed, got %v", err)
 	}
@@ -129,7 +188,7 @@ func TestClient_SendOrder_Invalid(t *testing.T) {
 		OrderID: 1,
 	}
 
-	err := client.SendOrder(order)
+	_, err := client.SendOrder(order)
 	if err == nil {
 		t.Error("expected error for invalid order")
 	}
@@ -173,6 +232,12 @@ func TestClient_Channels(t *testing.T) {
 	if client.CancelAcks() == nil {
 		t.Error("CancelAcks channel should not be nil")
 	}
+	if client.Rejects() == nil {
+		t.Error("Rejects channel should not be nil")
+	}
+	if client.Unknown() == nil {
+		t.Error("Unknown channel should not be nil")
+	}
 	if client.Errors() == nil {
 		t.Error("Errors channel should not be nil")
 	}
@@ -189,3 +254,561 @@ func TestClient_IsConnected_BeforeConnect(t *testing.T) {
 		t.Error("should not be connected before Connect()")
 	}
 }
+
+func TestClient_Protocol_Initial(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport Transport
+		protocol  Protocol
+		want      Protocol
+	}{
+		{"tcp auto pending", TransportTCP, ProtocolAuto, ProtocolAuto},
+		{"udp auto is csv", TransportUDP, ProtocolAuto, ProtocolCSV},
+		{"explicit csv", TransportTCP, ProtocolCSV, ProtocolCSV},
+		{"explicit binary", TransportTCP, ProtocolBinary, ProtocolBinary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig("localhost:1234")
+			cfg.Transport = tt.transport
+			cfg.Protocol = tt.protocol
+			client, _ := New(cfg)
+
+			if got := client.Protocol(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// binaryEngineReply behaves like a binary-only engine: it ignores frames
+// it cannot parse, rejects cancels and acks new orders.
+func binaryEngineReply(_ int, frame string) ([]string, bool) {
+	body := []byte(frame)
+	if len(body) < 2 || body[0] != protocol.BinaryMagic {
+		return nil, false
+	}
+
+	var reply []byte
+	switch body[1] {
+	case 'C':
+		// Reject: magic, 'R', symbol[16], user_id, order_id, reason
+		reply = make([]byte, protocol.BinaryRejectSize, protocol.BinaryRejectSize+16)
+		reply[1] = 'R'
+		copy(reply[2+protocol.BinarySymbolSize:], body[2:10])
+		reply = append(reply, "order not found"...)
+	case 'N':
+		// Ack: magic, 'A', symbol[16], user_id, order_id
+		reply = make([]byte, protocol.BinaryAckSize)
+		reply[1] = 'A'
+		copy(reply[2:], body[6:6+protocol.BinarySymbolSize])
+		copy(reply[2+protocol.BinarySymbolSize:], body[2:6])
+		copy(reply[6+protocol.BinarySymbolSize:], body[len(body)-4:])
+	default:
+		return nil, false
+	}
+	reply[0] = protocol.BinaryMagic
+	return []string{string(reply)}, false
+}
+
+func TestClient_AutoDetectsBinary(t *testing.T) {
+	// The server never speaks first, so the client must probe
+	server := startTestServer(t, binaryEngineReply)
+	client := newTestClient(t, server.addr, func(cfg *Config) { cfg.Protocol = ProtocolAuto })
+	mustConnect(t, client)
+
+	// Sent before detection completes; held until then
+	order := NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 10, Side: SideBuy, OrderID: 1001}
+	if _, err := client.SendOrder(order); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+
+	// Probes come first; the order must arrive binary-encoded
+	deadline := time.After(3 * time.Second)
+	for received := false; !received; {
+		select {
+		case frame := <-server.frames:
+			if len(frame) > 1 && frame[0] == protocol.BinaryMagic && frame[1] == 'N' {
+				if len(frame) != protocol.BinaryNewOrderSize {
+					t.Errorf("unexpected binary new order: % x", frame)
+				}
+				received = true
+			} else if strings.HasPrefix(frame, "N,") {
+				t.Fatalf("order sent as CSV: %q", frame)
+			}
+		case <-deadline:
+			t.Fatal("timed out waiting for order")
+		}
+	}
+
+	select {
+	case ack := <-client.Acks():
+		if ack.Symbol != "IBM" || ack.UserID != 1 || ack.OrderID != 1001 {
+			t.Errorf("unexpected ack: %+v", ack)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for ack")
+	}
+
+	if got := client.Protocol(); got != ProtocolBinary {
+		t.Errorf("expected negotiated protocol binary, got %v", got)
+	}
+	select {
+	case reject := <-client.Rejects():
+		t.Errorf("expected the probe reply to be consumed, got %+v", reject)
+	default:
+	}
+}
+
+func TestClient_AutoDetect_DeliversServerErrors(t *testing.T) {
+	// A CSV server answering the first probe with an error
+	server := startTestServer(t, func(_ int, frame string) ([]string, bool) {
+		return []string{"E, unparseable request"}, false
+	})
+	client := newTestClient(t, server.addr, func(cfg *Config) { cfg.Protocol = ProtocolAuto })
+	mustConnect(t, client)
+
+	select {
+	case reject := <-client.Rejects():
+		if reject.Reason != "unparseable request" {
+			t.Errorf("unexpected reject: %+v", reject)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("server error was swallowed as a probe reply")
+	}
+	if got := client.Protocol(); got != ProtocolCSV {
+		t.Errorf("expected negotiated protocol csv, got %v", got)
+	}
+}
+
+func TestClient_Rejects(t *testing.T) {
+	addr := startTestServer(t, nil, "R, IBM, 1, 1001, price outside collar, limit 105").addr
+
+	client := newTestClient(t, addr, nil)
+	mustConnect(t, client)
+
+	select {
+	case reject := <-client.Rejects():
+		want := Reject{Symbol: "IBM", UserID: 1, OrderID: 1001, Reason: "price outside collar, limit 105"}
+		if reject != want {
+			t.Errorf("expected %+v, got %+v", want, reject)
+		}
+	case err := <-client.Errors():
+		t.Fatalf("unexpected error: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reject")
+	}
+
+	if n := client.Stats().ErrorCount; n != 0 {
+		t.Errorf("expected no errors counted for a reject, got %d", n)
+	}
+}
+
+func TestClient_UnknownMessages_Skip(t *testing.T) {
+	addr := startTestServer(t, nil, "Q, IBM, 42", "A, IBM, 1, 1001").addr
+
+	client := newTestClient(t, addr, func(cfg *Config) { cfg.UnknownMessages = UnknownSkip })
+	mustConnect(t, client)
+
+	select {
+	case err := <-client.Errors():
+		if !errors.Is(err, ErrUnknownMessageType) {
+			t.Errorf("expected unknown message report, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for unknown message report")
+	}
+
+	select {
+	case ack := <-client.Acks():
+		if ack.OrderID != 1001 {
+			t.Errorf("unexpected ack: %+v", ack)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for ack after unknown frame")
+	}
+
+	snap := client.Stats()
+	if snap.UnknownMessages != 1 || snap.ReconnectCount != 0 {
+		t.Errorf("expected 1 unknown and no reconnects, got %+v", snap)
+	}
+}
+
+func TestClient_UnknownMessages_PassThrough(t *testing.T) {
+	addr := startTestServer(t, nil, "Q, IBM, 42", "A, IBM, 1, 1001").addr
+
+	client := newTestClient(t, addr, func(cfg *Config) { cfg.UnknownMessages = UnknownPassThrough })
+	mustConnect(t, client)
+
+	select {
+	case raw := <-client.Unknown():
+		if raw.Type != "Q" || string(raw.Payload) != "Q, IBM, 42" {
+			t.Errorf("unexpected raw message: %+v", raw)
+		}
+	case err := <-client.Errors():
+		t.Fatalf("unexpected error: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for raw message")
+	}
+
+	select {
+	case <-client.Acks():
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for ack after unknown frame")
+	}
+}
+
+func TestClient_TrackOrders(t *testing.T) {
+	addr := startTestServer(t, nil, "A, IBM, 1, 1001", "T, IBM, 1, 1001, 2, 2001, 100, 50").addr
+
+	client := newTestClient(t, addr, trackOrders)
+
+	order := NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 50, Side: SideBuy, OrderID: 1001}
+	if _, err := client.SendOrder(order); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	mustConnect(t, client)
+
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case ev := <-client.Orders().Events():
+			if ev.Order.State != orders.StateFilled {
+				continue
+			}
+			if ev.Order.FilledQty != 50 || len(ev.Order.Fills) != 1 {
+				t.Errorf("unexpected filled order: %+v", ev.Order)
+			}
+			if client.Orders().OpenCount() != 0 {
+				t.Error("expected no open orders")
+			}
+			return
+		case <-deadline:
+			t.Fatal("timed out waiting for fill")
+		}
+	}
+}
+
+// dropAfterAcks acks the first n orders on the first connection and
+// then drops it. On later connections cancels are acknowledged.
+func dropAfterAcks(n int) replyFunc {
+	acked := 0
+	return func(conn int, frame string) ([]string, bool) {
+		f := csvFields(frame)
+		switch {
+		case conn == 0 && f[0] == "N":
+			acked++
+			return []string{fmt.Sprintf("A, %s, %s, %s", f[2], f[1], f[6])}, acked == n
+		case conn > 0 && f[0] == "C":
+			return []string{fmt.Sprintf("C, IBM, %s, %s", f[1], f[2])}, false
+		}
+		return nil, false
+	}
+}
+
+// startDisconnectTest queues two orders on a tracking client with the
+// given policy, then connects it to a server that acks them and drops
+// the connection. The orders' frames are consumed from server.frames.
+func startDisconnectTest(t *testing.T, policy DisconnectPolicy) (*Client, *testServer) {
+	t.Helper()
+
+	server := startTestServer(t, dropAfterAcks(2))
+	client := newTestClient(t, server.addr, func(cfg *Config) {
+		cfg.TrackOrders = true
+		cfg.OnDisconnect = policy
+		cfg.ReconnectMinDelay = 10 * time.Millisecond
+	})
+
+	for id := uint32(1); id <= 2; id++ {
+		order := NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 10, Side: SideBuy, OrderID: id}
+		if _, err := client.SendOrder(order); err != nil {
+			t.Fatalf("send error: %v", err)
+		}
+	}
+	mustConnect(t, client)
+
+	expectFrames(t, server.frames, "N,1,IBM,100,10,B,1", "N,1,IBM,100,10,B,2")
+	return client, server
+}
+
+func waitReconnect(t *testing.T, client *Client) ReconnectEvent {
+	t.Helper()
+
+	select {
+	case ev := <-client.Reconnects():
+		return ev
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for reconnect")
+		return ReconnectEvent{}
+	}
+}
+
+func TestClient_CancelOnDisconnect(t *testing.T) {
+	client, server := startDisconnectTest(t, DisconnectCancel)
+
+	ev := waitReconnect(t, client)
+	if ev.UnknownOrders != 2 || ev.CancelsSent != 2 {
+		t.Errorf("unexpected reconnect event: %+v", ev)
+	}
+
+	expectFrames(t, server.frames, "C,1,1", "C,1,2")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for client.Orders().OpenCount() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected all orders cancelled, open: %+v", client.Orders().OpenOrders())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestClient_ReportOnDisconnect(t *testing.T) {
+	client, server := startDisconnectTest(t, DisconnectReport)
+
+	ev := waitReconnect(t, client)
+	if ev.UnknownOrders != 2 || ev.CancelsSent != 0 {
+		t.Errorf("unexpected reconnect event: %+v", ev)
+	}
+
+	open := client.Orders().OpenOrders()
+	if len(open) != 2 {
+		t.Fatalf("expected 2 open orders, got %d", len(open))
+	}
+	for _, o := range open {
+		if o.State != orders.StateUnknown {
+			t.Errorf("expected unknown state, got %+v", o)
+		}
+	}
+
+	select {
+	case f := <-server.frames:
+		t.Errorf("unexpected frame after reconnect: %q", f)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestClient_TrackBook(t *testing.T) {
+	addr := startTestServer(t, nil, "B, IBM, B, 100, 50", "B, IBM, S, 105, 20", "B, IBM, S, -, -").addr
+
+	client := newTestClient(t, addr, func(cfg *Config) { cfg.TrackBook = true })
+	mustConnect(t, client)
+
+	var events []BookEvent
+	for len(events) < 3 {
+		select {
+		case ev := <-client.Book().Events():
+			events = append(events, ev)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after %d book events", len(events))
+		}
+	}
+
+	if spread, ok := events[1].Quote.Spread(); !ok || spread != 5 {
+		t.Errorf("expected spread 5, got %d, %v", spread, ok)
+	}
+	q, _ := client.Book().Quote("IBM")
+	if q.Bid.Price != 100 || !q.Ask.IsEmpty() {
+		t.Errorf("expected empty ask after '-,-', got %+v", q)
+	}
+}
+
+func TestClient_TrackPositions(t *testing.T) {
+	addr := startTestServer(t, nil,
+		"T, IBM, 1, 1001, 2, 2001, 100, 50",
+		"B, IBM, B, 104, 10",
+		"B, IBM, S, 106, 10").addr
+
+	client := newTestClient(t, addr, func(cfg *Config) { cfg.TrackPositions = true })
+
+	order := NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 50, Side: SideBuy, OrderID: 1001}
+	if _, err := client.SendOrder(order); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	mustConnect(t, client)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		p, _ := client.Positions().Position("IBM")
+		if p.Mark == 105 {
+			if p.Net != 50 || p.AvgCost != 100 || p.Unrealized != 250 {
+				t.Errorf("unexpected position: %+v", p)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for mark, last %+v", p)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestClient_TrackLatency(t *testing.T) {
+	addr := startTestServer(t, engineReply).addr
+	client := newTestClient(t, addr, func(cfg *Config) { cfg.TrackLatency = true })
+	mustConnect(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	for id := uint32(1); id <= 3; id++ {
+		order := NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 10, Side: SideBuy, OrderID: id}
+		if _, err := client.PlaceOrder(ctx, order); err != nil {
+			t.Fatalf("place order error: %v", err)
+		}
+	}
+	rejected := NewOrder{UserID: 1, Symbol: "REJ", Price: 100, Qty: 10, Side: SideBuy, OrderID: 4}
+	if _, err := client.PlaceOrder(ctx, rejected); !errors.Is(err, ErrRejected) {
+		t.Fatalf("expected reject, got %v", err)
+	}
+
+	stats := client.Stats()
+	if stats.QueueLatency.Count != 4 || stats.AckLatency.Count != 3 {
+		t.Errorf("expected 4 queue and 3 ack samples, got %d and %d", stats.QueueLatency.Count, stats.AckLatency.Count)
+	}
+	ack := stats.AckLatency
+	if ack.Min <= 0 || ack.Max < ack.Mean() || ack.P99() > ack.Max || ack.P50() < ack.Min {
+		t.Errorf("unexpected ack latency: %+v p50=%v p99=%v", ack, ack.P50(), ack.P99())
+	}
+
+	_, interval := client.LatencyInterval()
+	if interval.Count != 3 {
+		t.Errorf("expected 3 ack samples in the interval, got %d", interval.Count)
+	}
+	if _, interval := client.LatencyInterval(); interval.Count != 0 {
+		t.Errorf("expected an empty second interval, got %d", interval.Count)
+	}
+	if n := client.Stats().AckLatency.Count; n != 3 {
+		t.Errorf("expected session total kept, got %d", n)
+	}
+	if n := client.Latency().Pending(); n != 0 {
+		t.Errorf("expected nothing pending, got %d", n)
+	}
+}
+
+func TestClient_MessageCounters(t *testing.T) {
+	frames := []string{"A, IBM, 1, 1001", "T, IBM, 1, 1001, 2, 2001, 100, 50", "B, AAPL, B, 175, 10"}
+	addr := startTestServer(t, nil, frames...).addr
+
+	client := newTestClient(t, addr, func(cfg *Config) { cfg.TrackSymbolStats = true })
+
+	if _, err := client.SendOrder(NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 50, Side: SideBuy, OrderID: 1001}); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if err := client.SendCancel(CancelOrder{UserID: 1, Symbol: "IBM", OrderID: 1001}); err != nil {
+		t.Fatalf("cancel error: %v", err)
+	}
+	if err := client.SendFlush(); err != nil {
+		t.Fatalf("flush error: %v", err)
+	}
+	mustConnect(t, client)
+
+	var wantBytes uint64
+	for _, f := range frames {
+		wantBytes += uint64(4 + len(f))
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	stats := client.Stats()
+	for (stats.MessagesReceived < 3 || stats.BytesSent == 0) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		stats = client.Stats()
+	}
+
+	if stats.NewOrdersSent != 1 || stats.CancelsSent != 1 || stats.FlushesSent != 1 || stats.MessagesSent != 3 {
+		t.Errorf("unexpected outbound counts: %+v", stats)
+	}
+	if stats.AcksReceived != 1 || stats.TradesReceived != 1 || stats.BookUpdatesReceived != 1 || stats.CancelAcksReceived != 0 {
+		t.Errorf("unexpected inbound counts: %+v", stats)
+	}
+	if stats.BytesReceived != wantBytes || stats.BytesSent == 0 {
+		t.Errorf("expected %d bytes received and some sent, got %d and %d", wantBytes, stats.BytesReceived, stats.BytesSent)
+	}
+
+	want := map[string]SymbolStats{
+		"IBM":  {NewOrdersSent: 1, CancelsSent: 1, Acks: 1, Trades: 1},
+		"AAPL": {BookUpdates: 1},
+	}
+	if !reflect.DeepEqual(stats.Symbols, want) {
+		t.Errorf("expected symbols %+v, got %+v", want, stats.Symbols)
+	}
+}
+
+func TestClient_TrackOrders_Disabled(t *testing.T) {
+	client, _ := New(DefaultConfig("localhost:1234"))
+	if client.Orders() != nil {
+		t.Error("expected nil order manager when tracking is off")
+	}
+	if client.Book() != nil {
+		t.Error("expected nil book when tracking is off")
+	}
+	if client.Positions() != nil {
+		t.Error("expected nil position keeper when tracking is off")
+	}
+	if client.Latency() != nil {
+		t.Error("expected nil latency tracker when tracking is off")
+	}
+}
+
+type countingCodec struct {
+	protocol.Codec
+	name     string
+	encoders chan struct{}
+}
+
+// countingCodecs numbers countingCodec names. Codecs cannot be
+// unregistered, so each test run registers a new name.
+var countingCodecs int32
+
+func (c *countingCodec) Name() string { return c.name }
+
+func (c *countingCodec) NewEncoder(w io.Writer) MessageEncoder {
+	c.encoders <- struct{}{}
+	return c.Codec.NewEncoder(w)
+}
+
+func TestClient_New_UnknownCodec(t *testing.T) {
+	cfg := DefaultConfig("localhost:1234")
+	cfg.Codec = "no-such-codec"
+
+	if _, err := New(cfg); err == nil {
+		t.Error("expected error for unknown codec")
+	}
+}
+
+func TestClient_CustomCodec(t *testing.T) {
+	csv, _ := LookupCodec(protocol.CodecCSV)
+	name := fmt.Sprintf("test-counting-%d", atomic.AddInt32(&countingCodecs, 1))
+	codec := &countingCodec{Codec: csv, name: name, encoders: make(chan struct{}, 4)}
+	if err := RegisterCodec(codec); err != nil {
+		t.Fatalf("register error: %v", err)
+	}
+
+	client := newTestClient(t, startTestServer(t, nil).addr, func(cfg *Config) { cfg.Codec = name })
+	mustConnect(t, client)
+
+	select {
+	case <-codec.encoders:
+	default:
+		t.Error("expected custom codec to create the encoder")
+	}
+	if got := client.Codec(); got != name {
+		t.Errorf("expected codec %q, got %q", name, got)
+	}
+}
+
+func TestClient_Codec_Builtin(t *testing.T) {
+	cfg := DefaultConfig("localhost:1234")
+	cfg.Protocol = ProtocolBinary
+	client, _ := New(cfg)
+
+	if got := client.Codec(); got != protocol.CodecBinary {
+		t.Errorf("expected codec binary, got %q", got)
+	}
+
+	cfg.Protocol = ProtocolAuto
+	client, _ = New(cfg)
+	if got := client.Codec(); got != "" {
+		t.Errorf("expected no codec while auto-detection pending, got %q", got)
+	}
+}
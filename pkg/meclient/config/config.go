@@ -7,6 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/orderid"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/ratelimit"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/risk"
 )
 
 // Default configuration values
@@ -19,15 +24,21 @@ const (
 	DefaultReconnectMinDelay = 100 * time.Millisecond
 	DefaultReconnectMaxDelay = 30 * time.Second
 	DefaultConnectTimeout    = 5 * time.Second
+	DefaultWriteBatchSize    = 256
+	DefaultSpillLimit        = 1 << 20
+	DefaultTerminalOrders    = 1 << 16
 )
 
 // Safety bounds
 const (
 	MaxReconnectAttempts   = 1000
 	MaxMessageBatchSize    = 1000
+	MaxWriteBatchSize      = 4096
 	MaxConsecutiveErrors   = 100
 	ReconnectCheckInterval = 50 * time.Millisecond
 	MaxSymbolLength        = 16
+	MaxDetectProbes        = 6                      // ProtocolAuto probes before falling back to CSV
+	DetectProbeInterval    = 500 * time.Millisecond // Wait for a reply before the next probe
 )
 
 // Transport mode
@@ -53,9 +64,9 @@ func (t Transport) String() string {
 type Protocol int
 
 const (
-	ProtocolAuto   Protocol = iota // Auto-detect (TCP: probe, UDP: CSV)
-	ProtocolCSV                    // CSV format
+	ProtocolCSV    Protocol = iota // CSV format
 	ProtocolBinary                 // Binary format
+	ProtocolAuto                   // Auto-detect, opt-in (TCP: probe and sniff first inbound frame, UDP: CSV)
 )
 
 func (p Protocol) String() string {
@@ -71,6 +82,123 @@ func (p Protocol) String() string {
 	}
 }
 
+// UnknownMessagePolicy controls how inbound frames of an unrecognized
+// message type are handled.
+type UnknownMessagePolicy int
+
+const (
+	UnknownStrict      UnknownMessagePolicy = iota // Treat as a read error and reconnect (default)
+	UnknownSkip                                    // Drop the frame and report it on Errors()
+	UnknownPassThrough                             // Deliver the raw frame on Unknown(); not with a Handler
+)
+
+func (p UnknownMessagePolicy) String() string {
+	switch p {
+	case UnknownStrict:
+		return "strict"
+	case UnknownSkip:
+		return "skip"
+	case UnknownPassThrough:
+		return "pass-through"
+	default:
+		return "unknown"
+	}
+}
+
+// Delivery selects how inbound messages reach the application.
+type Delivery int
+
+const (
+	DeliverChannels Delivery = iota // Per-type channels: Acks(), Trades(), ... (default)
+	DeliverEvents                   // Single Events() stream in wire order
+	DeliverBoth                     // Both; every channel in use must be drained
+)
+
+func (d Delivery) String() string {
+	switch d {
+	case DeliverChannels:
+		return "channels"
+	case DeliverEvents:
+		return "events"
+	case DeliverBoth:
+		return "both"
+	default:
+		return "unknown"
+	}
+}
+
+// DisconnectPolicy controls what happens to tracked orders that were open
+// when the connection dropped. Anything other than DisconnectKeep requires
+// TrackOrders.
+type DisconnectPolicy int
+
+const (
+	DisconnectKeep   DisconnectPolicy = iota // Leave orders as they are (default)
+	DisconnectReport                         // Mark them StateUnknown
+	DisconnectCancel                         // Mark them StateUnknown and cancel them after reconnecting
+)
+
+func (d DisconnectPolicy) String() string {
+	switch d {
+	case DisconnectKeep:
+		return "keep"
+	case DisconnectReport:
+		return "report"
+	case DisconnectCancel:
+		return "cancel"
+	default:
+		return "unknown"
+	}
+}
+
+// Backpressure is what happens when an inbound channel is full.
+type Backpressure int
+
+const (
+	BackpressureDefault    Backpressure = iota // Per-type default; see ApplyDefaults
+	BackpressureDropNewest                     // Drop the incoming message
+	BackpressureDropOldest                     // Discard the oldest queued message to make room
+	BackpressureBlock                          // Block the read loop until there is room
+	BackpressureSpill                          // Queue in memory up to Config.SpillLimit, then drop newest
+)
+
+func (b Backpressure) String() string {
+	switch b {
+	case BackpressureDefault:
+		return "default"
+	case BackpressureDropNewest:
+		return "drop-newest"
+	case BackpressureDropOldest:
+		return "drop-oldest"
+	case BackpressureBlock:
+		return "block"
+	case BackpressureSpill:
+		return "spill"
+	default:
+		return "unknown"
+	}
+}
+
+// BackpressurePolicy selects the Backpressure for each inbound channel.
+// Trades and Events default to spill so fills are never lost; the other
+// channels default to drop-newest.
+type BackpressurePolicy struct {
+	Acks        Backpressure
+	Trades      Backpressure
+	BookUpdates Backpressure
+	CancelAcks  Backpressure
+	Rejects     Backpressure
+	Unknown     Backpressure
+	Events      Backpressure
+}
+
+func (p *BackpressurePolicy) all() []*Backpressure {
+	return []*Backpressure{
+		&p.Acks, &p.Trades, &p.BookUpdates, &p.CancelAcks,
+		&p.Rejects, &p.Unknown, &p.Events,
+	}
+}
+
 // Sentinel errors
 var (
 	ErrInvalidConfig = errors.New("invalid configuration")
@@ -81,11 +209,30 @@ type Config struct {
 	Address           string
 	Transport         Transport // TCP or UDP
 	Protocol          Protocol  // CSV, Binary, or Auto
+	Codec             string    // Registered codec name; overrides Protocol when set
 	ChannelBuffer     int
 	ReconnectMinDelay time.Duration
 	ReconnectMaxDelay time.Duration
 	ConnectTimeout    time.Duration
 	AutoReconnect     bool
+	UnknownMessages   UnknownMessagePolicy // Handling of unrecognized message types
+	TrackOrders       bool                 // Track order state; see Client.Orders() and TerminalOrders
+	TrackBook         bool                 // Keep best bid/offer per symbol; see Client.Book()
+	TrackPositions    bool                 // Keep positions and P&L from fills; see Client.Positions()
+	TrackLatency      bool                 // Time orders enqueue→wire→ack; see Client.Latency()
+	TrackSymbolStats  bool                 // Count messages per symbol in Stats().Symbols
+	OnDisconnect      DisconnectPolicy     // Handling of open orders when the connection drops
+	Delivery          Delivery             // Per-type channels, Events(), or both
+	Handler           protocol.Handler     // Called inline instead of channel delivery when set
+	Backpressure      BackpressurePolicy   // Full-channel behavior per message type
+	SpillLimit        int                  // Max messages queued behind each spilling channel
+	TerminalOrders    int                  // Max finished orders TrackOrders keeps; the oldest are pruned
+	BlockingSends     bool                 // Send* wait for write queue space instead of failing
+	WriteBatchSize    int                  // Max queued requests encoded per flush; 1 flushes every message
+	WriteLinger       time.Duration        // Max wait for more requests before flushing a partial batch
+	Risk              *risk.Limits         // Pre-trade checks in SendOrder; nil disables them
+	RateLimit         *ratelimit.Limits    // Outbound token buckets; nil disables them
+	OrderIDs          orderid.Allocator    // Fills zero OrderIDs in SendOrder; nil uses a time-seeded counter
 }
 
 // Validate checks configuration for validity.
@@ -110,6 +257,56 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("%w: min delay cannot exceed max delay", ErrInvalidConfig)
 	}
 
+	if c.UnknownMessages < UnknownStrict || c.UnknownMessages > UnknownPassThrough {
+		return fmt.Errorf("%w: invalid unknown message policy %d", ErrInvalidConfig, c.UnknownMessages)
+	}
+
+	if c.Delivery < DeliverChannels || c.Delivery > DeliverBoth {
+		return fmt.Errorf("%w: invalid delivery mode %d", ErrInvalidConfig, c.Delivery)
+	}
+
+	// Nothing drains Unknown() or Events() in handler mode; UnknownSkip
+	// reports the frames to Handler.OnError instead
+	if c.Handler != nil && c.UnknownMessages == UnknownPassThrough {
+		return fmt.Errorf("%w: unknown pass-through cannot be used with a handler; use skip", ErrInvalidConfig)
+	}
+
+	if c.OnDisconnect < DisconnectKeep || c.OnDisconnect > DisconnectCancel {
+		return fmt.Errorf("%w: invalid disconnect policy %d", ErrInvalidConfig, c.OnDisconnect)
+	}
+
+	if c.OnDisconnect != DisconnectKeep && !c.TrackOrders {
+		return fmt.Errorf("%w: disconnect policy %s requires order tracking", ErrInvalidConfig, c.OnDisconnect)
+	}
+
+	for _, b := range c.Backpressure.all() {
+		if *b < BackpressureDefault || *b > BackpressureSpill {
+			return fmt.Errorf("%w: invalid backpressure policy %d", ErrInvalidConfig, *b)
+		}
+	}
+
+	if c.SpillLimit <= 0 {
+		return fmt.Errorf("%w: spill limit must be positive", ErrInvalidConfig)
+	}
+
+	if c.TerminalOrders <= 0 {
+		return fmt.Errorf("%w: terminal order count must be positive", ErrInvalidConfig)
+	}
+
+	if c.WriteBatchSize <= 0 || c.WriteBatchSize > MaxWriteBatchSize {
+		return fmt.Errorf("%w: write batch size must be 1-%d", ErrInvalidConfig, MaxWriteBatchSize)
+	}
+
+	if c.WriteLinger < 0 {
+		return fmt.Errorf("%w: write linger cannot be negative", ErrInvalidConfig)
+	}
+
+	if c.RateLimit != nil {
+		if err := c.RateLimit.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+	}
+
 	return nil
 }
 
@@ -133,17 +330,30 @@ func (c *Config) IsCSV() bool {
 	return c.Protocol == ProtocolCSV
 }
 
+// UsesChannels returns true if messages go to the per-type channels.
+func (c *Config) UsesChannels() bool {
+	return c.Delivery != DeliverEvents
+}
+
+// UsesEvents returns true if messages go to the Events() stream.
+func (c *Config) UsesEvents() bool {
+	return c.Delivery == DeliverEvents || c.Delivery == DeliverBoth
+}
+
 // Default returns a Config with sensible defaults.
 func Default(address string) Config {
 	return Config{
 		Address:           address,
 		Transport:         TransportTCP,
-		Protocol:          ProtocolAuto,
+		Protocol:          ProtocolCSV,
 		ChannelBuffer:     DefaultChannelBuffer,
 		ReconnectMinDelay: DefaultReconnectMinDelay,
 		ReconnectMaxDelay: DefaultReconnectMaxDelay,
 		ConnectTimeout:    DefaultConnectTimeout,
 		AutoReconnect:     true,
+		WriteBatchSize:    DefaultWriteBatchSize,
+		SpillLimit:        DefaultSpillLimit,
+		TerminalOrders:    DefaultTerminalOrders,
 	}
 }
 
@@ -161,5 +371,28 @@ func ApplyDefaults(cfg Config) Config {
 	if cfg.ConnectTimeout <= 0 {
 		cfg.ConnectTimeout = DefaultConnectTimeout
 	}
+	if cfg.WriteBatchSize <= 0 {
+		cfg.WriteBatchSize = DefaultWriteBatchSize
+	}
+	if cfg.SpillLimit <= 0 {
+		cfg.SpillLimit = DefaultSpillLimit
+	}
+	if cfg.TerminalOrders <= 0 {
+		cfg.TerminalOrders = DefaultTerminalOrders
+	}
+
+	// Losing a fill must be an explicit choice; the spill is still capped
+	// by SpillLimit so a consumer that never drains cannot grow it forever
+	if cfg.Backpressure.Trades == BackpressureDefault {
+		cfg.Backpressure.Trades = BackpressureSpill
+	}
+	if cfg.Backpressure.Events == BackpressureDefault {
+		cfg.Backpressure.Events = BackpressureSpill
+	}
+	for _, b := range cfg.Backpressure.all() {
+		if *b == BackpressureDefault {
+			*b = BackpressureDropNewest
+		}
+	}
 	return cfg
 }
@@ -3,8 +3,12 @@
 package config
 
 import (
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/ratelimit"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -16,6 +20,12 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.ChannelBuffer != DefaultChannelBuffer {
 		t.Errorf("expected channel buffer %d, got %d", DefaultChannelBuffer, cfg.ChannelBuffer)
 	}
+	if cfg.Protocol != ProtocolCSV {
+		t.Errorf("expected protocol csv, got %v", cfg.Protocol)
+	}
+	if zero := (Config{}); zero.Protocol != ProtocolCSV {
+		t.Errorf("expected zero-value protocol csv, got %v", zero.Protocol)
+	}
 }
 
 func TestConfigValidation_EmptyAddress(t *testing.T) {
@@ -99,6 +109,60 @@ func TestConfigValidation_MaxChannelBuffer(t *testing.T) {
 	}
 }
 
+func TestConfigValidation_UnknownMessagePolicy(t *testing.T) {
+	cfg := Default("localhost:12345")
+	if cfg.UnknownMessages != UnknownStrict {
+		t.Errorf("expected strict default, got %v", cfg.UnknownMessages)
+	}
+
+	cfg.UnknownMessages = UnknownPassThrough
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.UnknownMessages = UnknownPassThrough + 1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid policy")
+	}
+
+	// A handler has no channel to pass unknown frames through
+	cfg = Default("localhost:12345")
+	cfg.Handler = protocol.NopHandler{}
+	cfg.UnknownMessages = UnknownPassThrough
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for handler with pass-through, got %v", err)
+	}
+	cfg.UnknownMessages = UnknownSkip
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error for handler with skip: %v", err)
+	}
+}
+
+func TestConfigValidation_Delivery(t *testing.T) {
+	cfg := Default("localhost:12345")
+	if !cfg.UsesChannels() || cfg.UsesEvents() {
+		t.Error("expected channel delivery by default")
+	}
+
+	cfg.Delivery = DeliverEvents
+	if cfg.UsesChannels() || !cfg.UsesEvents() {
+		t.Error("expected events-only delivery")
+	}
+
+	cfg.Delivery = DeliverBoth
+	if !cfg.UsesChannels() || !cfg.UsesEvents() {
+		t.Error("expected both deliveries")
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Delivery = DeliverBoth + 1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid delivery mode")
+	}
+}
+
 func TestApplyDefaults_ZeroValues(t *testing.T) {
 	cfg := Config{
 		Address:           "localhost:12345",
@@ -122,6 +186,12 @@ func TestApplyDefaults_ZeroValues(t *testing.T) {
 	if cfg.ConnectTimeout != DefaultConnectTimeout {
 		t.Errorf("expected connect timeout %v, got %v", DefaultConnectTimeout, cfg.ConnectTimeout)
 	}
+	if cfg.TerminalOrders != DefaultTerminalOrders {
+		t.Errorf("expected terminal orders %d, got %d", DefaultTerminalOrders, cfg.TerminalOrders)
+	}
+	if cfg.WriteBatchSize != DefaultWriteBatchSize {
+		t.Errorf("expected write batch size %d, got %d", DefaultWriteBatchSize, cfg.WriteBatchSize)
+	}
 }
 
 func TestApplyDefaults_PreservesNonZeroValues(t *testing.T) {
@@ -149,6 +219,126 @@ func TestApplyDefaults_PreservesNonZeroValues(t *testing.T) {
 	}
 }
 
+func TestApplyDefaults_Backpressure(t *testing.T) {
+	cfg := ApplyDefaults(Config{Address: "localhost:12345"})
+
+	if cfg.Backpressure.Trades != BackpressureSpill || cfg.Backpressure.Events != BackpressureSpill {
+		t.Errorf("expected trades and events to spill, got %+v", cfg.Backpressure)
+	}
+	if cfg.Backpressure.Acks != BackpressureDropNewest || cfg.Backpressure.BookUpdates != BackpressureDropNewest {
+		t.Errorf("expected drop-newest for other channels, got %+v", cfg.Backpressure)
+	}
+	if cfg.SpillLimit != DefaultSpillLimit {
+		t.Errorf("expected spill limit %d, got %d", DefaultSpillLimit, cfg.SpillLimit)
+	}
+
+	cfg = Config{Address: "localhost:12345"}
+	cfg.Backpressure.Trades = BackpressureBlock
+	cfg = ApplyDefaults(cfg)
+	if cfg.Backpressure.Trades != BackpressureBlock {
+		t.Errorf("expected explicit policy preserved, got %v", cfg.Backpressure.Trades)
+	}
+}
+
+func TestConfigValidation_Backpressure(t *testing.T) {
+	cfg := Default("localhost:12345")
+	cfg.Backpressure.BookUpdates = BackpressureSpill + 1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid backpressure policy")
+	}
+
+	cfg = Default("localhost:12345")
+	cfg.SpillLimit = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for zero spill limit")
+	}
+
+	cfg = Default("localhost:12345")
+	cfg.TerminalOrders = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for zero terminal orders")
+	}
+}
+
+func TestConfigValidation_WriteBatch(t *testing.T) {
+	cfg := Default("localhost:12345")
+	cfg.WriteBatchSize = 1
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected batch size 1 to be valid, got %v", err)
+	}
+
+	cfg.WriteBatchSize = MaxWriteBatchSize + 1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for oversized write batch")
+	}
+
+	cfg = Default("localhost:12345")
+	cfg.WriteLinger = -time.Millisecond
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative write linger")
+	}
+}
+
+func TestConfigValidation_OnDisconnect(t *testing.T) {
+	cfg := Default("localhost:12345")
+	cfg.OnDisconnect = DisconnectCancel
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for cancel-on-disconnect without order tracking")
+	}
+
+	cfg.TrackOrders = true
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	cfg.OnDisconnect = DisconnectCancel + 1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid disconnect policy")
+	}
+}
+
+func TestConfigValidation_RateLimit(t *testing.T) {
+	cfg := Default("localhost:12345")
+	cfg.RateLimit = &ratelimit.Limits{OrdersPerSec: 1000, Mode: ratelimit.ModeReject}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	cfg.RateLimit = &ratelimit.Limits{OrdersPerSec: -1}
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for negative rate, got %v", err)
+	}
+}
+
+func TestDisconnectPolicyString(t *testing.T) {
+	tests := map[DisconnectPolicy]string{
+		DisconnectKeep:       "keep",
+		DisconnectReport:     "report",
+		DisconnectCancel:     "cancel",
+		DisconnectPolicy(99): "unknown",
+	}
+	for policy, want := range tests {
+		if got := policy.String(); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+}
+
+func TestBackpressureString(t *testing.T) {
+	tests := map[Backpressure]string{
+		BackpressureDefault:    "default",
+		BackpressureDropNewest: "drop-newest",
+		BackpressureDropOldest: "drop-oldest",
+		BackpressureBlock:      "block",
+		BackpressureSpill:      "spill",
+	}
+	for b, want := range tests {
+		if got := b.String(); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+}
+
 func TestTransportString(t *testing.T) {
 	if TransportTCP.String() != "tcp" {
 		t.Errorf("expected 'tcp', got %s", TransportTCP.String())
@@ -170,6 +360,18 @@ func TestProtocolString(t *testing.T) {
 	}
 }
 
+func TestUnknownMessagePolicyString(t *testing.T) {
+	if UnknownStrict.String() != "strict" {
+		t.Errorf("expected 'strict', got %s", UnknownStrict.String())
+	}
+	if UnknownSkip.String() != "skip" {
+		t.Errorf("expected 'skip', got %s", UnknownSkip.String())
+	}
+	if UnknownPassThrough.String() != "pass-through" {
+		t.Errorf("expected 'pass-through', got %s", UnknownPassThrough.String())
+	}
+}
+
 func TestConfigHelpers(t *testing.T) {
 	cfg := Default("localhost:1234")
 
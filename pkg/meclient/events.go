@@ -0,0 +1,95 @@
+// Full path: pkg/meclient/events.go
+
+package meclient
+
+import (
+	"time"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+// EventKind identifies which field of an Event is set.
+type EventKind uint8
+
+const (
+	EventNone       EventKind = iota // Zero value; never delivered
+	EventAck                         // Event.Ack
+	EventTrade                       // Event.Trade
+	EventBookUpdate                  // Event.BookUpdate
+	EventCancelAck                   // Event.CancelAck
+	EventReject                      // Event.Reject
+	EventRaw                         // Event.Raw (UnknownPassThrough only)
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventAck:
+		return "ack"
+	case EventTrade:
+		return "trade"
+	case EventBookUpdate:
+		return "book_update"
+	case EventCancelAck:
+		return "cancel_ack"
+	case EventReject:
+		return "reject"
+	case EventRaw:
+		return "raw"
+	default:
+		return "none"
+	}
+}
+
+// Event is one inbound message in wire order. Exactly the field named by
+// Kind is set.
+type Event struct {
+	Seq        uint64    // Receive sequence number, starting at 1
+	ReceivedAt time.Time // When the message was decoded
+	Kind       EventKind
+
+	Ack        protocol.Ack
+	Trade      protocol.Trade
+	BookUpdate protocol.BookUpdate
+	CancelAck  protocol.CancelAck
+	Reject     protocol.Reject
+	Raw        protocol.RawMessage
+}
+
+// nextEvent stamps a new event with the next sequence number.
+// Only the read loop calls it.
+func (c *Client) nextEvent(kind EventKind) Event {
+	c.recvSeq++
+	return Event{
+		Seq:        c.recvSeq,
+		ReceivedAt: time.Now(),
+		Kind:       kind,
+	}
+}
+
+// messageEvent converts a decoded message to an event.
+func (c *Client) messageEvent(msg *protocol.Message) Event {
+	switch {
+	case msg.Ack != nil:
+		ev := c.nextEvent(EventAck)
+		ev.Ack = *msg.Ack
+		return ev
+	case msg.Trade != nil:
+		ev := c.nextEvent(EventTrade)
+		ev.Trade = *msg.Trade
+		return ev
+	case msg.BookUpdate != nil:
+		ev := c.nextEvent(EventBookUpdate)
+		ev.BookUpdate = *msg.BookUpdate
+		return ev
+	case msg.CancelAck != nil:
+		ev := c.nextEvent(EventCancelAck)
+		ev.CancelAck = *msg.CancelAck
+		return ev
+	case msg.Reject != nil:
+		ev := c.nextEvent(EventReject)
+		ev.Reject = *msg.Reject
+		return ev
+	default:
+		return Event{}
+	}
+}
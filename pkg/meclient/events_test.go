@@ -0,0 +1,137 @@
+// Full path: pkg/meclient/events_test.go
+
+package meclient
+
+import (
+	"testing"
+	"time"
+)
+
+// deliverVia is a newTestClient mutator selecting delivery, with unknown
+// frames passed through.
+func deliverVia(delivery Delivery) func(cfg *Config) {
+	return func(cfg *Config) {
+		cfg.Delivery = delivery
+		cfg.UnknownMessages = UnknownPassThrough
+	}
+}
+
+func nextEvent(t *testing.T, client *Client) Event {
+	t.Helper()
+
+	select {
+	case ev := <-client.Events():
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return Event{}
+	}
+}
+
+func TestClient_Events_WireOrder(t *testing.T) {
+	addr := startTestServer(t, nil,
+		"A, IBM, 1, 1001",
+		"T, IBM, 1, 1001, 2, 2001, 100, 50",
+		"B, IBM, B, 99, 10",
+		"Q, something new",
+		"C, IBM, 1, 1002",
+		"R, IBM, 1, 1003, no liquidity",
+	).addr
+	client := newTestClient(t, addr, deliverVia(DeliverEvents))
+	mustConnect(t, client)
+
+	want := []EventKind{EventAck, EventTrade, EventBookUpdate, EventRaw, EventCancelAck, EventReject}
+
+	var last time.Time
+	for i, kind := range want {
+		ev := nextEvent(t, client)
+		if ev.Kind != kind {
+			t.Fatalf("event %d: expected %v, got %v", i, kind, ev.Kind)
+		}
+		if ev.Seq != uint64(i+1) {
+			t.Errorf("event %d: expected seq %d, got %d", i, i+1, ev.Seq)
+		}
+		if ev.ReceivedAt.Before(last) {
+			t.Errorf("event %d: timestamp went backwards", i)
+		}
+		last = ev.ReceivedAt
+
+		switch ev.Kind {
+		case EventTrade:
+			if ev.Trade.Qty != 50 {
+				t.Errorf("unexpected trade: %+v", ev.Trade)
+			}
+		case EventRaw:
+			if ev.Raw.Type != "Q" {
+				t.Errorf("unexpected raw message: %+v", ev.Raw)
+			}
+		case EventReject:
+			if ev.Reject.Reason != "no liquidity" {
+				t.Errorf("unexpected reject: %+v", ev.Reject)
+			}
+		}
+	}
+
+	// Events-only mode leaves the per-type channels empty
+	select {
+	case ack := <-client.Acks():
+		t.Errorf("unexpected ack on channel: %+v", ack)
+	case <-client.Unknown():
+		t.Error("unexpected raw message on channel")
+	default:
+	}
+}
+
+func TestClient_Events_Both(t *testing.T) {
+	addr := startTestServer(t, nil, "A, IBM, 1, 1001").addr
+	client := newTestClient(t, addr, deliverVia(DeliverBoth))
+	mustConnect(t, client)
+
+	if ev := nextEvent(t, client); ev.Kind != EventAck || ev.Ack.OrderID != 1001 {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+
+	select {
+	case ack := <-client.Acks():
+		if ack.OrderID != 1001 {
+			t.Errorf("unexpected ack: %+v", ack)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for ack on channel")
+	}
+}
+
+func TestClient_Events_ChannelsOnly(t *testing.T) {
+	addr := startTestServer(t, nil, "A, IBM, 1, 1001").addr
+	client := newTestClient(t, addr, deliverVia(DeliverChannels))
+	mustConnect(t, client)
+
+	select {
+	case <-client.Acks():
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for ack")
+	}
+
+	select {
+	case ev := <-client.Events():
+		t.Errorf("unexpected event: %+v", ev)
+	default:
+	}
+}
+
+func TestEventKindString(t *testing.T) {
+	tests := map[EventKind]string{
+		EventNone:       "none",
+		EventAck:        "ack",
+		EventTrade:      "trade",
+		EventBookUpdate: "book_update",
+		EventCancelAck:  "cancel_ack",
+		EventReject:     "reject",
+		EventRaw:        "raw",
+	}
+	for kind, want := range tests {
+		if got := kind.String(); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+}
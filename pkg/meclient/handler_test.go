@@ -0,0 +1,111 @@
+// Full path: pkg/meclient/handler_test.go
+
+package meclient
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingHandler records the callbacks it receives, in order.
+type recordingHandler struct {
+	NopHandler
+
+	mu     sync.Mutex
+	calls  []string
+	errors []error
+	done   chan struct{}
+	want   int
+}
+
+func newRecordingHandler(want int) *recordingHandler {
+	return &recordingHandler{done: make(chan struct{}), want: want}
+}
+
+func (h *recordingHandler) record(call string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.calls = append(h.calls, call)
+	if len(h.calls) == h.want {
+		close(h.done)
+	}
+}
+
+func (h *recordingHandler) OnAck(ack Ack)             { h.record("ack") }
+func (h *recordingHandler) OnTrade(trade Trade)       { h.record("trade") }
+func (h *recordingHandler) OnCancelAck(c CancelAck)   { h.record("cancel_ack") }
+func (h *recordingHandler) OnReject(reject Reject)    { h.record("reject") }
+func (h *recordingHandler) OnBookUpdate(u BookUpdate) { h.record("book") }
+
+func (h *recordingHandler) OnError(err error) {
+	h.mu.Lock()
+	h.errors = append(h.errors, err)
+	h.mu.Unlock()
+	h.record("error")
+}
+
+func TestClient_Handler_Inline(t *testing.T) {
+	addr := startTestServer(t, nil,
+		"A, IBM, 1, 1001",
+		"T, IBM, 1, 1001, 2, 2001, 100, 50",
+		"Q, something new",
+		"B, IBM, B, 99, 10",
+		"C, IBM, 1, 1002",
+		"R, IBM, 1, 1003, no liquidity",
+	).addr
+
+	h := newRecordingHandler(6)
+
+	client := newTestClient(t, addr, func(cfg *Config) {
+		cfg.Delivery = DeliverBoth
+		cfg.UnknownMessages = UnknownSkip
+		cfg.Handler = h
+	})
+	mustConnect(t, client)
+
+	select {
+	case <-h.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for callbacks")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	want := []string{"ack", "trade", "error", "book", "cancel_ack", "reject"}
+	for i, call := range want {
+		if h.calls[i] != call {
+			t.Errorf("call %d: expected %s, got %s", i, call, h.calls[i])
+		}
+	}
+	if len(h.errors) != 1 || !errors.Is(h.errors[0], ErrUnknownMessageType) {
+		t.Errorf("unexpected errors: %v", h.errors)
+	}
+
+	// The handler replaces channel and event delivery
+	select {
+	case <-client.Acks():
+		t.Error("unexpected ack on channel")
+	case <-client.Events():
+		t.Error("unexpected event")
+	case err := <-client.Errors():
+		t.Errorf("unexpected error on channel: %v", err)
+	default:
+	}
+}
+
+func TestNopHandler(t *testing.T) {
+	var h Handler = NopHandler{}
+
+	// Must not panic
+	h.OnAck(Ack{})
+	h.OnTrade(Trade{})
+	h.OnBookUpdate(BookUpdate{})
+	h.OnCancelAck(CancelAck{})
+	h.OnReject(Reject{})
+	h.OnError(errors.New("x"))
+	h.OnReconnect(ReconnectEvent{})
+}
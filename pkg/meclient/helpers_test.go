@@ -0,0 +1,175 @@
+// Full path: pkg/meclient/helpers_test.go
+
+package meclient
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient returns an unconnected CSV client for addr with mutate
+// applied to its config. It is closed when the test ends unless the test
+// closed it first.
+func newTestClient(t *testing.T, addr string, mutate func(cfg *Config)) *Client {
+	t.Helper()
+
+	cfg := DefaultConfig(addr)
+	cfg.Protocol = ProtocolCSV
+	if mutate != nil {
+		mutate(&cfg)
+	}
+	client, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() {
+		if client.ctx.Err() == nil {
+			client.Close()
+		}
+	})
+	return client
+}
+
+// mustConnect connects client or fails the test.
+func mustConnect(t *testing.T, client *Client) {
+	t.Helper()
+
+	if err := client.Connect(); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+}
+
+// trackOrders is a newTestClient mutator turning on order tracking.
+func trackOrders(cfg *Config) { cfg.TrackOrders = true }
+
+// replyFunc answers one frame read on connection conn, counting from 0.
+// It returns the frames to write back and whether to then drop the
+// connection.
+type replyFunc func(conn int, frame string) (replies []string, drop bool)
+
+// testServer is a fake engine for client tests.
+type testServer struct {
+	addr   string
+	frames <-chan string // Every frame read, as sent
+}
+
+// startTestServer accepts connections one at a time. On each it writes
+// the greeting frames, then reads frames, records them on frames and
+// writes back whatever reply returns; a nil reply answers nothing.
+func startTestServer(t *testing.T, reply replyFunc, greeting ...string) *testServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	frames := make(chan string, 256)
+	go func() {
+		for n := 0; ; n++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			serveTestConn(conn, n, reply, greeting, frames)
+		}
+	}()
+
+	return &testServer{addr: listener.Addr().String(), frames: frames}
+}
+
+func serveTestConn(conn net.Conn, n int, reply replyFunc, greeting []string, frames chan<- string) {
+	defer conn.Close()
+
+	for _, payload := range greeting {
+		if err := writeTestFrame(conn, payload); err != nil {
+			return
+		}
+	}
+
+	var header [4]byte
+	for {
+		if _, err := io.ReadFull(conn, header[:]); err != nil {
+			return
+		}
+		body := make([]byte, binary.BigEndian.Uint32(header[:]))
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return
+		}
+		select {
+		case frames <- string(body):
+		default:
+		}
+		if reply == nil {
+			continue
+		}
+
+		replies, drop := reply(n, string(body))
+		for _, payload := range replies {
+			if err := writeTestFrame(conn, payload); err != nil {
+				return
+			}
+		}
+		if drop {
+			// Give the client time to read the replies
+			time.Sleep(50 * time.Millisecond)
+			return
+		}
+	}
+}
+
+func writeTestFrame(conn net.Conn, payload string) error {
+	frame := make([]byte, 4+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(len(payload)))
+	copy(frame[4:], payload)
+	_, err := conn.Write(frame)
+	return err
+}
+
+// csvFields splits a CSV frame into its fields.
+func csvFields(frame string) []string {
+	return strings.Split(strings.TrimSpace(frame), ",")
+}
+
+// engineReply answers CSV requests the way the engine would: orders are
+// acked, except symbol "REJ" which is rejected and symbol "SLOW" which
+// gets no reply; cancels are acked, except those from user 9 which are
+// rejected.
+func engineReply(_ int, frame string) ([]string, bool) {
+	f := csvFields(frame)
+	switch {
+	case f[0] == "N" && f[2] == "REJ":
+		return []string{fmt.Sprintf("R, %s, %s, %s, symbol halted", f[2], f[1], f[6])}, false
+	case f[0] == "N" && f[2] == "SLOW":
+		return nil, false
+	case f[0] == "N":
+		return []string{fmt.Sprintf("A, %s, %s, %s", f[2], f[1], f[6])}, false
+	case f[0] == "C" && f[1] == "9":
+		return []string{fmt.Sprintf("R, IBM, %s, %s, cancel refused", f[1], f[2])}, false
+	case f[0] == "C":
+		return []string{fmt.Sprintf("C, IBM, %s, %s", f[1], f[2])}, false
+	}
+	return nil, false
+}
+
+// expectFrames reads len(want) frames and compares them, trimmed, to want.
+func expectFrames(t *testing.T, frames <-chan string, want ...string) {
+	t.Helper()
+
+	for i, w := range want {
+		select {
+		case got := <-frames:
+			if got = strings.TrimSpace(got); got != w {
+				t.Errorf("frame %d: expected %q, got %q", i, w, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for frame %d", i)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// Full path: pkg/meclient/internal/stats/histogram.go
+
+package stats
+
+import (
+	"math"
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// Histogram layout. Durations below 2*subBucketCount nanoseconds are
+// counted exactly; above that each power of two is split into
+// subBucketCount linear buckets, bounding the relative error of a
+// reported value to 1/subBucketCount (about 1.6%).
+const (
+	subBucketBits  = 6
+	subBucketCount = 1 << subBucketBits
+
+	// Durations of 2^maxValueBits ns (about 69s) or more share the top bucket
+	maxValueBits = 36
+	maxValue     = 1<<maxValueBits - 1
+
+	numBuckets = (maxValueBits - subBucketBits + 1) * subBucketCount
+)
+
+// Histogram is an HDR-style latency histogram. Record is lock-free and
+// safe to call from any goroutine.
+type Histogram struct {
+	counts [numBuckets]uint64
+	sum    uint64 // nanoseconds
+	minInv uint64 // math.MaxUint64 - min, so the zero value means "none"
+	max    uint64
+}
+
+// Record adds one duration. Negative durations count as zero.
+func (h *Histogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	v := uint64(d)
+
+	atomic.AddUint64(&h.counts[bucketIndex(v)], 1)
+	atomic.AddUint64(&h.sum, v)
+	storeMax(&h.minInv, math.MaxUint64-v)
+	storeMax(&h.max, v)
+}
+
+// Snapshot returns a copy of the histogram. Durations recorded while it
+// runs may be partly included.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	return h.read(atomic.LoadUint64)
+}
+
+// Interval returns a copy of the histogram and resets it, so successive
+// calls report disjoint intervals. A duration recorded concurrently is
+// counted in exactly one interval, though its contribution to Sum, Min
+// or Max may land in the next.
+func (h *Histogram) Interval() HistogramSnapshot {
+	return h.read(func(addr *uint64) uint64 {
+		return atomic.SwapUint64(addr, 0)
+	})
+}
+
+// Reset clears the histogram.
+func (h *Histogram) Reset() {
+	h.Interval()
+}
+
+func (h *Histogram) read(load func(*uint64) uint64) HistogramSnapshot {
+	var s HistogramSnapshot
+
+	last := -1
+	var counts [numBuckets]uint64
+	for i := range h.counts {
+		if counts[i] = load(&h.counts[i]); counts[i] != 0 {
+			s.Count += counts[i]
+			last = i
+		}
+	}
+	s.counts = append([]uint64(nil), counts[:last+1]...)
+
+	s.Sum = time.Duration(load(&h.sum))
+	minInv := load(&h.minInv)
+	s.Max = time.Duration(load(&h.max))
+	if s.Count > 0 {
+		s.Min = time.Duration(math.MaxUint64 - minInv)
+	}
+	return s
+}
+
+// storeMax raises *addr to v if v is larger.
+func storeMax(addr *uint64, v uint64) {
+	for {
+		cur := atomic.LoadUint64(addr)
+		if v <= cur || atomic.CompareAndSwapUint64(addr, cur, v) {
+			return
+		}
+	}
+}
+
+// bucketIndex returns the bucket counting v nanoseconds.
+func bucketIndex(v uint64) int {
+	if v > maxValue {
+		v = maxValue
+	}
+	if v < 2*subBucketCount {
+		return int(v)
+	}
+	shift := bits.Len64(v) - 1 - subBucketBits
+	return shift*subBucketCount + int(v>>shift)
+}
+
+// bucketHigh returns the largest value counted in bucket i.
+func bucketHigh(i int) uint64 {
+	if i < 2*subBucketCount {
+		return uint64(i)
+	}
+	shift := i/subBucketCount - 1
+	low := uint64(i-shift*subBucketCount) << shift
+	return low + 1<<shift - 1
+}
+
+// HistogramSnapshot is a point-in-time copy of a Histogram.
+type HistogramSnapshot struct {
+	Count uint64
+	Sum   time.Duration
+	Min   time.Duration
+	Max   time.Duration
+
+	counts []uint64 // per bucket, trailing empty buckets trimmed
+}
+
+// Mean returns the average duration, or zero if the snapshot is empty.
+func (s HistogramSnapshot) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+// Percentile returns the duration at or below which p percent of the
+// recorded durations fall, within the histogram's precision. p is
+// clamped to 0-100; 100 returns Max.
+func (s HistogramSnapshot) Percentile(p float64) time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	if p >= 100 {
+		return s.Max
+	}
+	if p < 0 {
+		p = 0
+	}
+
+	target := uint64(math.Ceil(p / 100 * float64(s.Count)))
+	if target == 0 {
+		target = 1
+	}
+
+	var seen uint64
+	for i, n := range s.counts {
+		if seen += n; seen >= target {
+			v := time.Duration(bucketHigh(i))
+			if v > s.Max {
+				v = s.Max
+			}
+			return v
+		}
+	}
+	return s.Max
+}
+
+// P50 returns the median.
+func (s HistogramSnapshot) P50() time.Duration { return s.Percentile(50) }
+
+// P90 returns the 90th percentile.
+func (s HistogramSnapshot) P90() time.Duration { return s.Percentile(90) }
+
+// P99 returns the 99th percentile.
+func (s HistogramSnapshot) P99() time.Duration { return s.Percentile(99) }
+
+// P999 returns the 99.9th percentile.
+func (s HistogramSnapshot) P999() time.Duration { return s.Percentile(99.9) }
+
+// Merge returns the combination of s and o, as if every duration had
+// been recorded into one histogram.
+func (s HistogramSnapshot) Merge(o HistogramSnapshot) HistogramSnapshot {
+	if o.Count == 0 {
+		return s
+	}
+	if s.Count == 0 {
+		return o
+	}
+
+	m := HistogramSnapshot{
+		Count: s.Count + o.Count,
+		Sum:   s.Sum + o.Sum,
+		Min:   s.Min,
+		Max:   s.Max,
+	}
+	if o.Min < m.Min {
+		m.Min = o.Min
+	}
+	if o.Max > m.Max {
+		m.Max = o.Max
+	}
+
+	m.counts = make([]uint64, max(len(s.counts), len(o.counts)))
+	copy(m.counts, s.counts)
+	for i, n := range o.counts {
+		m.counts[i] += n
+	}
+	return m
+}
@@ -0,0 +1,185 @@
+// Full path: pkg/meclient/internal/stats/histogram_test.go
+
+package stats
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// withinPrecision reports whether got is within the histogram's relative
+// error of want.
+func withinPrecision(got, want time.Duration) bool {
+	diff := got - want
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= want/subBucketCount+1
+}
+
+func TestBucketIndex_RoundTrip(t *testing.T) {
+	prev := -1
+	for _, v := range []uint64{0, 1, 63, 64, 127, 128, 129, 255, 256, 1000, 1 << 20, 123456789, maxValue} {
+		i := bucketIndex(v)
+		if i < prev || i >= numBuckets {
+			t.Fatalf("value %d: bucket %d out of order or range", v, i)
+		}
+		prev = i
+
+		high := bucketHigh(i)
+		if high < v || (i > 0 && bucketHigh(i-1) >= v) {
+			t.Errorf("value %d: bucket %d covers up to %d, previous up to %d", v, i, high, bucketHigh(i-1))
+		}
+	}
+
+	if bucketIndex(maxValue+1000) != numBuckets-1 {
+		t.Error("expected values past the range in the top bucket")
+	}
+}
+
+func TestHistogram_Percentiles(t *testing.T) {
+	var h Histogram
+
+	// 1µs..1000µs, one each
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Microsecond)
+	}
+
+	s := h.Snapshot()
+	if s.Count != 1000 || s.Min != time.Microsecond || s.Max != time.Millisecond {
+		t.Fatalf("unexpected snapshot: count=%d min=%v max=%v", s.Count, s.Min, s.Max)
+	}
+	if s.Mean() != 500500*time.Nanosecond {
+		t.Errorf("expected mean 500.5µs, got %v", s.Mean())
+	}
+
+	tests := []struct {
+		name string
+		got  time.Duration
+		want time.Duration
+	}{
+		{"p50", s.P50(), 500 * time.Microsecond},
+		{"p90", s.P90(), 900 * time.Microsecond},
+		{"p99", s.P99(), 990 * time.Microsecond},
+		{"p99.9", s.P999(), 999 * time.Microsecond},
+		{"p100", s.Percentile(100), time.Millisecond},
+		{"p0", s.Percentile(0), time.Microsecond},
+	}
+	for _, tt := range tests {
+		if !withinPrecision(tt.got, tt.want) {
+			t.Errorf("%s: expected ~%v, got %v", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+func TestHistogram_Empty(t *testing.T) {
+	var h Histogram
+
+	s := h.Snapshot()
+	if s.Count != 0 || s.Min != 0 || s.Max != 0 || s.Mean() != 0 || s.P99() != 0 {
+		t.Errorf("expected empty snapshot, got %+v", s)
+	}
+
+	h.Record(-time.Second)
+	if s := h.Snapshot(); s.Count != 1 || s.Max != 0 {
+		t.Errorf("expected negative duration counted as zero, got %+v", s)
+	}
+}
+
+func TestHistogram_Interval(t *testing.T) {
+	var h Histogram
+
+	h.Record(10 * time.Microsecond)
+	h.Record(20 * time.Microsecond)
+	first := h.Interval()
+
+	h.Record(5 * time.Microsecond)
+	second := h.Interval()
+
+	if first.Count != 2 || first.Max != 20*time.Microsecond {
+		t.Errorf("unexpected first interval: %+v", first)
+	}
+	if second.Count != 1 || second.Min != 5*time.Microsecond || second.Max != 5*time.Microsecond {
+		t.Errorf("unexpected second interval: %+v", second)
+	}
+	if s := h.Snapshot(); s.Count != 0 {
+		t.Errorf("expected histogram reset by Interval, got %d", s.Count)
+	}
+
+	merged := first.Merge(second)
+	if merged.Count != 3 || merged.Min != 5*time.Microsecond || merged.Max != 20*time.Microsecond {
+		t.Errorf("unexpected merge: %+v", merged)
+	}
+	if merged.Sum != 35*time.Microsecond || !withinPrecision(merged.P50(), 10*time.Microsecond) {
+		t.Errorf("unexpected merged sum %v or p50 %v", merged.Sum, merged.P50())
+	}
+	if m := merged.Merge(HistogramSnapshot{}); m.Count != 3 {
+		t.Errorf("expected merging an empty snapshot to change nothing, got %+v", m)
+	}
+}
+
+func TestHistogram_Concurrent(t *testing.T) {
+	var h Histogram
+
+	const workers, each = 8, 10000
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				h.Record(time.Duration(w*each+i) * time.Nanosecond)
+			}
+		}(w)
+	}
+
+	// Intervals taken while recording must not lose or double count
+	var total uint64
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for {
+		select {
+		case <-done:
+			total += h.Interval().Count
+			if total != workers*each {
+				t.Errorf("expected %d recorded, got %d", workers*each, total)
+			}
+			return
+		default:
+			total += h.Interval().Count
+		}
+	}
+}
+
+func TestStats_Latency(t *testing.T) {
+	s := &Stats{}
+
+	s.RecordQueueLatency(2 * time.Microsecond)
+	s.RecordAckLatency(40 * time.Microsecond)
+	s.RecordAckLatency(60 * time.Microsecond)
+
+	queue, ack := s.LatencyInterval()
+	if queue.Count != 1 || ack.Count != 2 {
+		t.Fatalf("unexpected interval: queue=%d ack=%d", queue.Count, ack.Count)
+	}
+
+	s.RecordAckLatency(80 * time.Microsecond)
+	if _, ack := s.LatencyInterval(); ack.Count != 1 || ack.Max != 80*time.Microsecond {
+		t.Errorf("unexpected second interval: %+v", ack)
+	}
+
+	// The snapshot keeps the session totals across intervals
+	snap := s.GetSnapshot()
+	if snap.AckLatency.Count != 3 || snap.AckLatency.Min != 40*time.Microsecond || snap.QueueLatency.Count != 1 {
+		t.Errorf("unexpected snapshot latency: ack=%+v queue=%+v", snap.AckLatency, snap.QueueLatency)
+	}
+
+	s.Reset()
+	if snap := s.GetSnapshot(); snap.AckLatency.Count != 0 || snap.QueueLatency.Count != 0 {
+		t.Error("expected latency cleared by Reset")
+	}
+}
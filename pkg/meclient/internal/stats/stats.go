@@ -3,7 +3,11 @@
 // Package stats provides thread-safe statistics tracking.
 package stats
 
-import "sync/atomic"
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 // Snapshot is a point-in-time copy of statistics.
 type Snapshot struct {
@@ -12,6 +16,41 @@ type Snapshot struct {
 	ErrorCount       uint64
 	ReconnectCount   uint64
 	DroppedMessages  uint64
+	UnknownMessages  uint64
+
+	// Backpressure outcomes on full inbound channels
+	DroppedNewest uint64 // Incoming message dropped
+	DroppedOldest uint64 // Queued message discarded to make room
+	BlockedSends  uint64 // Read loop waited for room
+	Spilled       uint64 // Message queued in the spill buffer
+
+	WriteBatches uint64 // Transport flushes by the write loop
+	RiskRejects  uint64 // Orders refused by pre-trade risk checks
+	RateLimited  uint64 // Sends refused by the rate limiter
+	RateDelayed  uint64 // Sends that waited for the rate limiter
+
+	// Order latency, when tracked; see RecordQueueLatency and RecordAckLatency
+	QueueLatency HistogramSnapshot // Enqueue to wire
+	AckLatency   HistogramSnapshot // Wire to first response
+
+	// Outbound messages by type, counted with MessagesSent
+	NewOrdersSent uint64
+	CancelsSent   uint64
+	FlushesSent   uint64
+
+	// Inbound messages by type, counted with MessagesReceived;
+	// unrecognized messages are UnknownMessages
+	AcksReceived        uint64
+	TradesReceived      uint64
+	BookUpdatesReceived uint64
+	CancelAcksReceived  uint64
+	RejectsReceived     uint64
+
+	BytesSent     uint64 // Encoded bytes written to the transport
+	BytesReceived uint64 // Bytes read from the transport
+
+	// Per-symbol counts; nil unless any were recorded with IncSymbol
+	Symbols map[string]SymbolSnapshot
 }
 
 // Stats tracks client statistics with atomic operations.
@@ -21,6 +60,64 @@ type Stats struct {
 	errorCount       uint64
 	reconnectCount   uint64
 	droppedMessages  uint64
+	unknownMessages  uint64
+	droppedNewest    uint64
+	droppedOldest    uint64
+	blockedSends     uint64
+	spilled          uint64
+	writeBatches     uint64
+	riskRejects      uint64
+	rateLimited      uint64
+	rateDelayed      uint64
+
+	newOrdersSent       uint64
+	cancelsSent         uint64
+	flushesSent         uint64
+	acksReceived        uint64
+	tradesReceived      uint64
+	bookUpdatesReceived uint64
+	cancelAcksReceived  uint64
+	rejectsReceived     uint64
+	bytesSent           uint64
+	bytesReceived       uint64
+
+	queueLatency latencyStats
+	ackLatency   latencyStats
+
+	symbols symbolStats
+}
+
+// latencyStats is a live histogram plus the intervals already read from
+// it, so Interval can reset the live histogram without losing the
+// session totals that GetSnapshot reports.
+type latencyStats struct {
+	live Histogram
+
+	mu    sync.Mutex
+	total HistogramSnapshot
+}
+
+func (l *latencyStats) snapshot() HistogramSnapshot {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.total.Merge(l.live.Snapshot())
+}
+
+func (l *latencyStats) interval() HistogramSnapshot {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	iv := l.live.Interval()
+	l.total = l.total.Merge(iv)
+	return iv
+}
+
+func (l *latencyStats) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.live.Reset()
+	l.total = HistogramSnapshot{}
 }
 
 // IncMessagesSent increments the sent message counter.
@@ -48,6 +145,120 @@ func (s *Stats) IncDroppedMessages() {
 	atomic.AddUint64(&s.droppedMessages, 1)
 }
 
+// IncUnknownMessages increments the unrecognized message counter.
+func (s *Stats) IncUnknownMessages() {
+	atomic.AddUint64(&s.unknownMessages, 1)
+}
+
+// IncDroppedNewest records a message dropped by the drop-newest policy.
+func (s *Stats) IncDroppedNewest() {
+	atomic.AddUint64(&s.droppedNewest, 1)
+}
+
+// IncDroppedOldest records a message discarded by the drop-oldest policy.
+func (s *Stats) IncDroppedOldest() {
+	atomic.AddUint64(&s.droppedOldest, 1)
+}
+
+// IncBlockedSends records a send that blocked under the block policy.
+func (s *Stats) IncBlockedSends() {
+	atomic.AddUint64(&s.blockedSends, 1)
+}
+
+// IncSpilled records a message queued by the spill policy.
+func (s *Stats) IncSpilled() {
+	atomic.AddUint64(&s.spilled, 1)
+}
+
+// IncWriteBatches records a batch of requests flushed to the transport.
+func (s *Stats) IncWriteBatches() {
+	atomic.AddUint64(&s.writeBatches, 1)
+}
+
+// IncRiskRejects records an order refused by a risk check.
+func (s *Stats) IncRiskRejects() {
+	atomic.AddUint64(&s.riskRejects, 1)
+}
+
+// IncRateLimited records a send refused by the rate limiter.
+func (s *Stats) IncRateLimited() {
+	atomic.AddUint64(&s.rateLimited, 1)
+}
+
+// IncRateDelayed records a send that waited for the rate limiter.
+func (s *Stats) IncRateDelayed() {
+	atomic.AddUint64(&s.rateDelayed, 1)
+}
+
+// IncNewOrdersSent records a new order queued for sending.
+func (s *Stats) IncNewOrdersSent() {
+	atomic.AddUint64(&s.newOrdersSent, 1)
+}
+
+// IncCancelsSent records a cancel queued for sending.
+func (s *Stats) IncCancelsSent() {
+	atomic.AddUint64(&s.cancelsSent, 1)
+}
+
+// IncFlushesSent records a flush queued for sending.
+func (s *Stats) IncFlushesSent() {
+	atomic.AddUint64(&s.flushesSent, 1)
+}
+
+// IncAcksReceived records a received order acknowledgement.
+func (s *Stats) IncAcksReceived() {
+	atomic.AddUint64(&s.acksReceived, 1)
+}
+
+// IncTradesReceived records a received trade.
+func (s *Stats) IncTradesReceived() {
+	atomic.AddUint64(&s.tradesReceived, 1)
+}
+
+// IncBookUpdatesReceived records a received book update.
+func (s *Stats) IncBookUpdatesReceived() {
+	atomic.AddUint64(&s.bookUpdatesReceived, 1)
+}
+
+// IncCancelAcksReceived records a received cancel acknowledgement.
+func (s *Stats) IncCancelAcksReceived() {
+	atomic.AddUint64(&s.cancelAcksReceived, 1)
+}
+
+// IncRejectsReceived records a received reject.
+func (s *Stats) IncRejectsReceived() {
+	atomic.AddUint64(&s.rejectsReceived, 1)
+}
+
+// AddBytesSent records n bytes written to the transport.
+func (s *Stats) AddBytesSent(n int) {
+	atomic.AddUint64(&s.bytesSent, uint64(n))
+}
+
+// AddBytesReceived records n bytes read from the transport.
+func (s *Stats) AddBytesReceived(n int) {
+	atomic.AddUint64(&s.bytesReceived, uint64(n))
+}
+
+// RecordQueueLatency records the time an order spent between the write
+// queue and the wire.
+func (s *Stats) RecordQueueLatency(d time.Duration) {
+	s.queueLatency.live.Record(d)
+}
+
+// RecordAckLatency records the time from an order reaching the wire to
+// its first response.
+func (s *Stats) RecordAckLatency(d time.Duration) {
+	s.ackLatency.live.Record(d)
+}
+
+// LatencyInterval returns the queue and ack latency recorded since the
+// previous call (or since the start) and begins a new interval. Session
+// totals in GetSnapshot are unaffected.
+func (s *Stats) LatencyInterval() (queue, ack HistogramSnapshot) {
+	return s.queueLatency.interval(), s.ackLatency.interval()
+}
+
 // GetSnapshot returns a point-in-time copy of all statistics.
 func (s *Stats) GetSnapshot() Snapshot {
 	return Snapshot{
@@ -56,6 +267,30 @@ func (s *Stats) GetSnapshot() Snapshot {
 		ErrorCount:       atomic.LoadUint64(&s.errorCount),
 		ReconnectCount:   atomic.LoadUint64(&s.reconnectCount),
 		DroppedMessages:  atomic.LoadUint64(&s.droppedMessages),
+		UnknownMessages:  atomic.LoadUint64(&s.unknownMessages),
+		DroppedNewest:    atomic.LoadUint64(&s.droppedNewest),
+		DroppedOldest:    atomic.LoadUint64(&s.droppedOldest),
+		BlockedSends:     atomic.LoadUint64(&s.blockedSends),
+		Spilled:          atomic.LoadUint64(&s.spilled),
+		WriteBatches:     atomic.LoadUint64(&s.writeBatches),
+		RiskRejects:      atomic.LoadUint64(&s.riskRejects),
+		RateLimited:      atomic.LoadUint64(&s.rateLimited),
+		RateDelayed:      atomic.LoadUint64(&s.rateDelayed),
+		QueueLatency:     s.queueLatency.snapshot(),
+		AckLatency:       s.ackLatency.snapshot(),
+
+		NewOrdersSent:       atomic.LoadUint64(&s.newOrdersSent),
+		CancelsSent:         atomic.LoadUint64(&s.cancelsSent),
+		FlushesSent:         atomic.LoadUint64(&s.flushesSent),
+		AcksReceived:        atomic.LoadUint64(&s.acksReceived),
+		TradesReceived:      atomic.LoadUint64(&s.tradesReceived),
+		BookUpdatesReceived: atomic.LoadUint64(&s.bookUpdatesReceived),
+		CancelAcksReceived:  atomic.LoadUint64(&s.cancelAcksReceived),
+		RejectsReceived:     atomic.LoadUint64(&s.rejectsReceived),
+		BytesSent:           atomic.LoadUint64(&s.bytesSent),
+		BytesReceived:       atomic.LoadUint64(&s.bytesReceived),
+
+		Symbols: s.symbols.snapshot(),
 	}
 }
 
@@ -66,4 +301,26 @@ func (s *Stats) Reset() {
 	atomic.StoreUint64(&s.errorCount, 0)
 	atomic.StoreUint64(&s.reconnectCount, 0)
 	atomic.StoreUint64(&s.droppedMessages, 0)
+	atomic.StoreUint64(&s.unknownMessages, 0)
+	atomic.StoreUint64(&s.droppedNewest, 0)
+	atomic.StoreUint64(&s.droppedOldest, 0)
+	atomic.StoreUint64(&s.blockedSends, 0)
+	atomic.StoreUint64(&s.spilled, 0)
+	atomic.StoreUint64(&s.writeBatches, 0)
+	atomic.StoreUint64(&s.riskRejects, 0)
+	atomic.StoreUint64(&s.rateLimited, 0)
+	atomic.StoreUint64(&s.rateDelayed, 0)
+	atomic.StoreUint64(&s.newOrdersSent, 0)
+	atomic.StoreUint64(&s.cancelsSent, 0)
+	atomic.StoreUint64(&s.flushesSent, 0)
+	atomic.StoreUint64(&s.acksReceived, 0)
+	atomic.StoreUint64(&s.tradesReceived, 0)
+	atomic.StoreUint64(&s.bookUpdatesReceived, 0)
+	atomic.StoreUint64(&s.cancelAcksReceived, 0)
+	atomic.StoreUint64(&s.rejectsReceived, 0)
+	atomic.StoreUint64(&s.bytesSent, 0)
+	atomic.StoreUint64(&s.bytesReceived, 0)
+	s.queueLatency.reset()
+	s.ackLatency.reset()
+	s.symbols.reset()
 }
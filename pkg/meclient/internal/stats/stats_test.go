@@ -3,6 +3,7 @@
 package stats
 
 import (
+	"fmt"
 	"sync"
 	"testing"
 )
@@ -16,6 +17,26 @@ func TestStatsIncrement(t *testing.T) {
 	s.IncErrorCount()
 	s.IncReconnectCount()
 	s.IncDroppedMessages()
+	s.IncUnknownMessages()
+	s.IncDroppedNewest()
+	s.IncDroppedOldest()
+	s.IncBlockedSends()
+	s.IncSpilled()
+	s.IncWriteBatches()
+	s.IncRiskRejects()
+	s.IncRateLimited()
+	s.IncRateDelayed()
+	s.IncNewOrdersSent()
+	s.IncCancelsSent()
+	s.IncFlushesSent()
+	s.IncAcksReceived()
+	s.IncTradesReceived()
+	s.IncBookUpdatesReceived()
+	s.IncCancelAcksReceived()
+	s.IncRejectsReceived()
+	s.AddBytesSent(30)
+	s.AddBytesReceived(12)
+	s.AddBytesReceived(8)
 
 	snap := s.GetSnapshot()
 
@@ -34,6 +55,79 @@ func TestStatsIncrement(t *testing.T) {
 	if snap.DroppedMessages != 1 {
 		t.Errorf("expected DroppedMessages=1, got %d", snap.DroppedMessages)
 	}
+	if snap.UnknownMessages != 1 {
+		t.Errorf("expected UnknownMessages=1, got %d", snap.UnknownMessages)
+	}
+	if snap.DroppedNewest != 1 || snap.DroppedOldest != 1 || snap.BlockedSends != 1 || snap.Spilled != 1 {
+		t.Errorf("unexpected backpressure counters: %+v", snap)
+	}
+	if snap.WriteBatches != 1 {
+		t.Errorf("expected WriteBatches=1, got %d", snap.WriteBatches)
+	}
+	if snap.RiskRejects != 1 {
+		t.Errorf("expected RiskRejects=1, got %d", snap.RiskRejects)
+	}
+	if snap.RateLimited != 1 || snap.RateDelayed != 1 {
+		t.Errorf("unexpected rate limit counters: %+v", snap)
+	}
+	if snap.NewOrdersSent != 1 || snap.CancelsSent != 1 || snap.FlushesSent != 1 {
+		t.Errorf("unexpected outbound type counters: %+v", snap)
+	}
+	if snap.AcksReceived != 1 || snap.TradesReceived != 1 || snap.BookUpdatesReceived != 1 ||
+		snap.CancelAcksReceived != 1 || snap.RejectsReceived != 1 {
+		t.Errorf("unexpected inbound type counters: %+v", snap)
+	}
+	if snap.BytesSent != 30 || snap.BytesReceived != 20 {
+		t.Errorf("expected 30 bytes sent and 20 received, got %d and %d", snap.BytesSent, snap.BytesReceived)
+	}
+	if snap.Symbols != nil {
+		t.Errorf("expected no symbol counters, got %+v", snap.Symbols)
+	}
+}
+
+func TestStatsSymbols(t *testing.T) {
+	s := &Stats{}
+
+	s.IncSymbol("IBM", SymbolNewOrdersSent)
+	s.IncSymbol("IBM", SymbolAcks)
+	s.IncSymbol("IBM", SymbolTrades)
+	s.IncSymbol("IBM", SymbolTrades)
+	s.IncSymbol("AAPL", SymbolBookUpdates)
+	s.IncSymbol("AAPL", SymbolDropped)
+	s.IncSymbol("AAPL", numSymbolCounters) // ignored
+
+	snap := s.GetSnapshot()
+	if ibm := snap.Symbols["IBM"]; ibm != (SymbolSnapshot{NewOrdersSent: 1, Acks: 1, Trades: 2}) {
+		t.Errorf("unexpected IBM counters: %+v", ibm)
+	}
+	if aapl := snap.Symbols["AAPL"]; aapl != (SymbolSnapshot{BookUpdates: 1, Dropped: 1}) {
+		t.Errorf("unexpected AAPL counters: %+v", aapl)
+	}
+
+	s.Reset()
+	if snap := s.GetSnapshot(); snap.Symbols != nil {
+		t.Errorf("expected symbols cleared by Reset, got %+v", snap.Symbols)
+	}
+}
+
+func TestStatsSymbols_Limit(t *testing.T) {
+	s := &Stats{}
+
+	for i := 0; i < MaxSymbols+2; i++ {
+		s.IncSymbol(fmt.Sprintf("S%d", i), SymbolTrades)
+	}
+	s.IncSymbol("S0", SymbolTrades)
+
+	snap := s.GetSnapshot()
+	if len(snap.Symbols) != MaxSymbols+1 {
+		t.Errorf("expected %d symbols including %q, got %d", MaxSymbols+1, OtherSymbols, len(snap.Symbols))
+	}
+	if n := snap.Symbols[OtherSymbols].Trades; n != 2 {
+		t.Errorf("expected 2 trades past the limit, got %d", n)
+	}
+	if n := snap.Symbols["S0"].Trades; n != 2 {
+		t.Errorf("expected counted symbols kept, got %d", n)
+	}
 }
 
 func TestStatsReset(t *testing.T) {
@@ -0,0 +1,118 @@
+// Full path: pkg/meclient/internal/stats/symbols.go
+
+package stats
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// MaxSymbols bounds the symbols counted individually. Messages for
+// symbols beyond the limit are counted under OtherSymbols.
+const MaxSymbols = 4096
+
+// OtherSymbols is the Snapshot.Symbols key for symbols past MaxSymbols.
+const OtherSymbols = "*"
+
+// SymbolCounter selects one of a symbol's counters.
+type SymbolCounter int
+
+const (
+	SymbolNewOrdersSent SymbolCounter = iota
+	SymbolCancelsSent
+	SymbolAcks
+	SymbolTrades
+	SymbolBookUpdates
+	SymbolCancelAcks
+	SymbolRejects
+	SymbolDropped
+
+	numSymbolCounters
+)
+
+// SymbolSnapshot is a point-in-time copy of one symbol's counters.
+type SymbolSnapshot struct {
+	NewOrdersSent uint64
+	CancelsSent   uint64
+	Acks          uint64
+	Trades        uint64
+	BookUpdates   uint64
+	CancelAcks    uint64
+	Rejects       uint64
+	Dropped       uint64 // Outbound or inbound messages dropped
+}
+
+type symbolCounts [numSymbolCounters]uint64
+
+// symbolStats holds counters per symbol. Existing symbols are counted
+// under the read lock; only a new symbol takes the write lock.
+type symbolStats struct {
+	mu     sync.RWMutex
+	counts map[string]*symbolCounts
+}
+
+// IncSymbol increments one of symbol's counters.
+func (s *Stats) IncSymbol(symbol string, c SymbolCounter) {
+	if c < 0 || c >= numSymbolCounters {
+		return
+	}
+	atomic.AddUint64(&s.symbols.get(symbol)[c], 1)
+}
+
+func (t *symbolStats) get(symbol string) *symbolCounts {
+	t.mu.RLock()
+	counts, ok := t.counts[symbol]
+	t.mu.RUnlock()
+	if ok {
+		return counts
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if counts, ok := t.counts[symbol]; ok {
+		return counts
+	}
+	if t.counts == nil {
+		t.counts = make(map[string]*symbolCounts)
+	}
+	if len(t.counts) >= MaxSymbols {
+		if counts, ok := t.counts[OtherSymbols]; ok {
+			return counts
+		}
+		symbol = OtherSymbols
+	}
+	counts = new(symbolCounts)
+	t.counts[symbol] = counts
+	return counts
+}
+
+func (t *symbolStats) snapshot() map[string]SymbolSnapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if len(t.counts) == 0 {
+		return nil
+	}
+
+	m := make(map[string]SymbolSnapshot, len(t.counts))
+	for symbol, counts := range t.counts {
+		m[symbol] = SymbolSnapshot{
+			NewOrdersSent: atomic.LoadUint64(&counts[SymbolNewOrdersSent]),
+			CancelsSent:   atomic.LoadUint64(&counts[SymbolCancelsSent]),
+			Acks:          atomic.LoadUint64(&counts[SymbolAcks]),
+			Trades:        atomic.LoadUint64(&counts[SymbolTrades]),
+			BookUpdates:   atomic.LoadUint64(&counts[SymbolBookUpdates]),
+			CancelAcks:    atomic.LoadUint64(&counts[SymbolCancelAcks]),
+			Rejects:       atomic.LoadUint64(&counts[SymbolRejects]),
+			Dropped:       atomic.LoadUint64(&counts[SymbolDropped]),
+		}
+	}
+	return m
+}
+
+func (t *symbolStats) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.counts = nil
+}
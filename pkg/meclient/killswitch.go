@@ -0,0 +1,138 @@
+// Full path: pkg/meclient/killswitch.go
+
+package meclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/orders"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+// Kill switch errors
+var (
+	ErrTradingHalted       = errors.New("trading halted")
+	ErrOrderTrackingNeeded = errors.New("order tracking is off; open orders unknown")
+	ErrCancelRejected      = errors.New("engine rejected cancels; orders left open")
+)
+
+// killPollInterval is how often KillSwitch checks for cancelled orders.
+const killPollInterval = 5 * time.Millisecond
+
+// KillReport describes the outcome of KillSwitch.
+type KillReport struct {
+	CancelsSent int            // Cancels queued for open orders
+	Closed      int            // Orders that reached a terminal state
+	Residual    []orders.Order // Orders still open when KillSwitch returned
+	Refused     []orders.Order // Residual orders whose cancel was rejected
+	SendErrors  []error        // Cancels that could not be queued
+	Elapsed     time.Duration
+}
+
+// KillSwitch halts trading and cancels every tracked open order.
+//
+// New orders fail with ErrTradingHalted from the moment it is called
+// until Resume; cancels and flushes still go out. Its own cancels wait
+// for the rate limiter rather than being refused under RateLimitReject,
+// as cancel-on-disconnect's do. It then waits until
+// every open order is cancelled, filled or rejected, or ctx ends, and
+// reports any residual orders with ctx's error. Orders that slip in while
+// the switch is thrown are cancelled too.
+//
+// An order whose cancel the engine rejects is still resting. Once only
+// such orders are left, KillSwitch stops waiting and returns them in
+// Residual and Refused with ErrCancelRejected.
+//
+// It needs Config.TrackOrders to know which orders are open; without it
+// trading is halted and ErrOrderTrackingNeeded is returned.
+func (c *Client) KillSwitch(ctx context.Context) (KillReport, error) {
+	start := time.Now()
+	atomic.StoreInt32(&c.halted, 1)
+
+	var report KillReport
+	if c.orders == nil {
+		return report, ErrOrderTrackingNeeded
+	}
+
+	// Keyed by order; true once the cancel is queued
+	cancelled := make(map[orders.Key]bool)
+	ticker := time.NewTicker(killPollInterval)
+	defer ticker.Stop()
+
+	for {
+		open := c.orders.OpenOrders()
+		refused := 0
+
+		for i := range open {
+			o := &open[i]
+			if queued, ok := cancelled[o.Key()]; ok {
+				// Still open with no cancel outstanding: it was refused
+				if queued && !o.CancelPending {
+					refused++
+				}
+				continue
+			}
+
+			err := c.sendCancel(ctx, true, writeRequest{mustSend: true, cancel: protocol.CancelOrder{
+				Symbol:  o.Symbol,
+				UserID:  o.UserID,
+				OrderID: o.OrderID,
+			}})
+			if err != nil {
+				report.SendErrors = append(report.SendErrors,
+					fmt.Errorf("cancel user=%d order=%d: %w", o.UserID, o.OrderID, err))
+			} else {
+				report.CancelsSent++
+			}
+			cancelled[o.Key()] = err == nil
+		}
+
+		if len(open) == 0 {
+			report.Closed = len(cancelled)
+			report.Elapsed = time.Since(start)
+			return report, nil
+		}
+		if refused == len(open) {
+			return c.killResidual(report, cancelled, start), ErrCancelRejected
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return c.killResidual(report, cancelled, start), ctx.Err()
+		case <-c.ctx.Done():
+			return c.killResidual(report, cancelled, start), ErrClientClosed
+		}
+	}
+}
+
+// killResidual fills in the report for orders still open when KillSwitch
+// gives up.
+func (c *Client) killResidual(report KillReport, cancelled map[orders.Key]bool, start time.Time) KillReport {
+	report.Residual = c.orders.OpenOrders()
+	for _, o := range report.Residual {
+		if cancelled[o.Key()] && !o.CancelPending {
+			report.Refused = append(report.Refused, o)
+		}
+	}
+	report.Closed = len(cancelled) - len(report.Residual)
+	if report.Closed < 0 {
+		report.Closed = 0
+	}
+	report.Elapsed = time.Since(start)
+	return report
+}
+
+// Resume lifts a halt set by KillSwitch.
+func (c *Client) Resume() {
+	atomic.StoreInt32(&c.halted, 0)
+}
+
+// Halted returns true while KillSwitch has halted new orders.
+func (c *Client) Halted() bool {
+	return atomic.LoadInt32(&c.halted) != 0
+}
@@ -0,0 +1,146 @@
+// Full path: pkg/meclient/killswitch_test.go
+
+package meclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/orders"
+)
+
+func TestClient_KillSwitch(t *testing.T) {
+	client := newTestClient(t, startTestServer(t, engineReply).addr, trackOrders)
+	mustConnect(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	for id := uint32(1); id <= 3; id++ {
+		order := NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 10, Side: SideBuy, OrderID: id}
+		if _, err := client.PlaceOrder(ctx, order); err != nil {
+			t.Fatalf("place order %d: %v", id, err)
+		}
+	}
+
+	report, err := client.KillSwitch(ctx)
+	if err != nil {
+		t.Fatalf("kill switch error: %v", err)
+	}
+	if report.CancelsSent != 3 || report.Closed != 3 || len(report.Residual) != 0 || len(report.SendErrors) != 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if n := client.Orders().OpenCount(); n != 0 {
+		t.Errorf("expected no open orders, got %d", n)
+	}
+
+	if !client.Halted() {
+		t.Error("expected trading halted")
+	}
+	order := NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 10, Side: SideBuy, OrderID: 4}
+	if _, err := client.SendOrder(order); err != ErrTradingHalted {
+		t.Errorf("expected ErrTradingHalted, got %v", err)
+	}
+	if err := client.SendCancel(CancelOrder{UserID: 1, OrderID: 1}); err != nil {
+		t.Errorf("expected cancels while halted, got %v", err)
+	}
+
+	client.Resume()
+	if _, err := client.SendOrder(order); err != nil {
+		t.Errorf("expected orders after resume, got %v", err)
+	}
+}
+
+func TestClient_KillSwitch_Residual(t *testing.T) {
+	// The server never answers, so no cancel is confirmed
+	client := newTestClient(t, startTestServer(t, nil).addr, trackOrders)
+	mustConnect(t, client)
+
+	for id := uint32(1); id <= 2; id++ {
+		order := NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 10, Side: SideBuy, OrderID: id}
+		if _, err := client.SendOrder(order); err != nil {
+			t.Fatalf("send order %d: %v", id, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	report, err := client.KillSwitch(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if report.CancelsSent != 2 || report.Closed != 0 || len(report.Residual) != 2 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestClient_KillSwitch_CancelRejected(t *testing.T) {
+	client := newTestClient(t, startTestServer(t, engineReply).addr, trackOrders)
+	mustConnect(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// The responder rejects cancels from user 9
+	for _, user := range []uint32{1, 9} {
+		order := NewOrder{UserID: user, Symbol: "IBM", Price: 100, Qty: 10, Side: SideBuy, OrderID: 1}
+		if _, err := client.PlaceOrder(ctx, order); err != nil {
+			t.Fatalf("place order for user %d: %v", user, err)
+		}
+	}
+
+	report, err := client.KillSwitch(ctx)
+	if err != ErrCancelRejected {
+		t.Fatalf("expected ErrCancelRejected, got %v", err)
+	}
+	if report.CancelsSent != 2 || report.Closed != 1 || len(report.Residual) != 1 || len(report.Refused) != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if o := report.Refused[0]; o.UserID != 9 || o.State != orders.StateAcked || o.Reason != "cancel refused" {
+		t.Errorf("unexpected refused order: %+v", o)
+	}
+	if n := client.Orders().OpenCount(); n != 1 {
+		t.Errorf("expected the refused order still open, got %d", n)
+	}
+}
+
+func TestClient_KillSwitch_RateLimited(t *testing.T) {
+	limits := RateLimits{MessagesPerSec: 1, Burst: 1, Mode: RateLimitReject}
+	client := newTestClient(t, startTestServer(t, engineReply).addr, func(cfg *Config) {
+		cfg.TrackOrders = true
+		cfg.RateLimit = &limits
+	})
+	mustConnect(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// The order takes the only token, so the cancel must wait for the next
+	order := NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 10, Side: SideBuy, OrderID: 1}
+	if _, err := client.PlaceOrder(ctx, order); err != nil {
+		t.Fatalf("place order: %v", err)
+	}
+
+	report, err := client.KillSwitch(ctx)
+	if err != nil {
+		t.Fatalf("kill switch error: %v (report %+v)", err, report)
+	}
+	if report.CancelsSent != 1 || report.Closed != 1 || len(report.SendErrors) != 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestClient_KillSwitch_NoTracking(t *testing.T) {
+	client, _ := New(DefaultConfig("localhost:1234"))
+	defer client.Close()
+
+	if _, err := client.KillSwitch(context.Background()); err != ErrOrderTrackingNeeded {
+		t.Errorf("expected ErrOrderTrackingNeeded, got %v", err)
+	}
+	if _, err := client.SendOrder(testOrder); err != ErrTradingHalted {
+		t.Errorf("expected trading halted anyway, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+// Full path: pkg/meclient/latency/latency.go
+
+// Package latency measures per-order latency through the client.
+//
+// The enqueue→wire interval, from an order entering the write queue to the
+// flush that put it on the wire, is known when the flush completes. The
+// wire→ack interval, from that flush to the first response naming the
+// order (an Ack, Trade or CancelAck), needs the wire time kept until the
+// response arrives; a Tracker holds it. Both intervals are recorded into
+// the client's statistics. Tracker is safe for concurrent use.
+package latency
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultMaxPending bounds the orders awaiting a response. Orders written
+// while the limit is reached are not timed to their response.
+const DefaultMaxPending = 1 << 16
+
+// key identifies an order. Order IDs are scoped to a user.
+type key struct {
+	userID  uint32
+	orderID uint32
+}
+
+// Tracker times orders from wire to response.
+type Tracker struct {
+	mu         sync.Mutex
+	pending    map[key]time.Time // wire time of orders awaiting a response
+	maxPending int
+	skipped    uint64
+}
+
+// NewTracker creates a tracker holding up to maxPending orders awaiting
+// a response. Zero uses DefaultMaxPending.
+func NewTracker(maxPending int) *Tracker {
+	if maxPending <= 0 {
+		maxPending = DefaultMaxPending
+	}
+	return &Tracker{
+		pending:    make(map[key]time.Time),
+		maxPending: maxPending,
+	}
+}
+
+// Written records that an order reached the wire at written, and starts
+// waiting for its response.
+func (t *Tracker) Written(userID, orderID uint32, written time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.pending) >= t.maxPending {
+		t.skipped++
+		return
+	}
+	t.pending[key{userID, orderID}] = written
+}
+
+// Responded returns the wire→ack latency of an order awaiting its first
+// response and stops waiting for it. It reports false for an order not
+// awaited, including later responses for the same order.
+func (t *Tracker) Responded(userID, orderID uint32, at time.Time) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	written, ok := t.pending[key{userID, orderID}]
+	if !ok {
+		return 0, false
+	}
+	delete(t.pending, key{userID, orderID})
+	return at.Sub(written), true
+}
+
+// Forget stops waiting for an order's response without recording it,
+// e.g. when the order is rejected.
+func (t *Tracker) Forget(userID, orderID uint32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, key{userID, orderID})
+}
+
+// ClearPending stops waiting for every outstanding response, e.g. when
+// the connection drops.
+func (t *Tracker) ClearPending() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = make(map[key]time.Time)
+}
+
+// Pending returns the number of orders awaiting a response.
+func (t *Tracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
+
+// Skipped returns the number of orders not timed to their response
+// because too many were pending.
+func (t *Tracker) Skipped() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.skipped
+}
+
+// Reset clears all pending orders and the skipped count.
+func (t *Tracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = make(map[key]time.Time)
+	t.skipped = 0
+}
@@ -0,0 +1,58 @@
+// Full path: pkg/meclient/latency/latency_test.go
+
+package latency
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracker_Responded(t *testing.T) {
+	tr := NewTracker(0)
+	start := time.Unix(1700000000, 0)
+
+	tr.Written(1, 100, start)
+	tr.Written(1, 101, start.Add(10*time.Microsecond))
+	if n := tr.Pending(); n != 2 {
+		t.Fatalf("expected 2 pending, got %d", n)
+	}
+
+	if d, ok := tr.Responded(1, 100, start.Add(50*time.Microsecond)); !ok || d != 50*time.Microsecond {
+		t.Errorf("expected 50µs, got %v, %v", d, ok)
+	}
+	// e.g. the trade after the ack
+	if _, ok := tr.Responded(1, 100, start.Add(90*time.Microsecond)); ok {
+		t.Error("expected only the first response timed")
+	}
+	// not ours
+	if _, ok := tr.Responded(2, 101, start.Add(90*time.Microsecond)); ok {
+		t.Error("expected another user's order ignored")
+	}
+
+	tr.Forget(1, 101)
+	if n := tr.Pending(); n != 0 {
+		t.Errorf("expected nothing pending, got %d", n)
+	}
+}
+
+func TestTracker_MaxPending(t *testing.T) {
+	tr := NewTracker(1)
+	now := time.Now()
+
+	tr.Written(1, 1, now)
+	tr.Written(1, 2, now)
+	if tr.Pending() != 1 || tr.Skipped() != 1 {
+		t.Errorf("expected 1 pending and 1 skipped, got %d and %d", tr.Pending(), tr.Skipped())
+	}
+
+	tr.ClearPending()
+	if tr.Pending() != 0 {
+		t.Error("expected pending cleared")
+	}
+
+	tr.Written(1, 3, now)
+	tr.Reset()
+	if tr.Skipped() != 0 || tr.Pending() != 0 {
+		t.Error("expected tracker reset")
+	}
+}
@@ -0,0 +1,33 @@
+// Full path: pkg/meclient/metered.go
+
+package meclient
+
+import (
+	"io"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/internal/stats"
+)
+
+// meteredReader counts the bytes read from the transport.
+type meteredReader struct {
+	r     io.Reader
+	stats *stats.Stats
+}
+
+func (cr *meteredReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.stats.AddBytesReceived(n)
+	return n, err
+}
+
+// meteredWriter counts the bytes written to the transport.
+type meteredWriter struct {
+	w     io.Writer
+	stats *stats.Stats
+}
+
+func (cw *meteredWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.stats.AddBytesSent(n)
+	return n, err
+}
@@ -0,0 +1,199 @@
+// Full path: pkg/meclient/orderid/orderid.go
+
+// Package orderid allocates client order IDs.
+//
+// The engine identifies an order by (UserID, OrderID), so IDs must not be
+// reused while an earlier order with the same ID may still be live. Three
+// allocators are provided:
+//
+//   - Monotonic counts up from a fixed start.
+//   - NewTimeSeeded starts a Monotonic from the Unix time, so runs
+//     started apart usually do not overlap; see its limits.
+//   - File persists its position, so IDs keep increasing across runs.
+//
+// All allocators are safe for concurrent use. Zero is never returned; the
+// client treats a zero OrderID as "allocate one".
+package orderid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultFileBlock is the number of IDs File reserves per write.
+const DefaultFileBlock = 1000
+
+// Errors
+var (
+	ErrExhausted   = errors.New("order IDs exhausted")
+	ErrInvalidFile = errors.New("invalid order ID file")
+)
+
+// Allocator hands out order IDs.
+type Allocator interface {
+	// Next returns an unused, non-zero order ID, or ErrExhausted.
+	Next() (uint32, error)
+}
+
+// Monotonic hands out consecutive IDs from a start value.
+type Monotonic struct {
+	last uint64 // last ID handed out; accessed atomically
+}
+
+// NewMonotonic returns an allocator whose first ID is start. A zero start
+// begins at 1.
+func NewMonotonic(start uint32) *Monotonic {
+	if start == 0 {
+		start = 1
+	}
+	return &Monotonic{last: uint64(start) - 1}
+}
+
+// timeSeedPeriod is the span of NewTimeSeeded start values, 2^31 ms or
+// about 24.8 days. The upper half of the ID space is left to count into.
+const timeSeedPeriod = 1 << 31
+
+// NewTimeSeeded returns a Monotonic starting at the milliseconds since
+// the Unix epoch modulo 2^31, plus one.
+//
+// Its limits: a run reuses an earlier run's IDs if the earlier one sent
+// more orders than milliseconds passed between the two starts, and start
+// values repeat every 24.8 days. Processes running at the same time with
+// the same user ID collide just the same; give each its own user ID.
+func NewTimeSeeded() *Monotonic {
+	return NewMonotonic(timeSeed(time.Now()))
+}
+
+func timeSeed(t time.Time) uint32 {
+	return uint32(uint64(t.UnixMilli())%timeSeedPeriod) + 1
+}
+
+// Next returns the next ID.
+func (m *Monotonic) Next() (uint32, error) {
+	id := atomic.AddUint64(&m.last, 1)
+	if id > math.MaxUint32 {
+		return 0, ErrExhausted
+	}
+	return uint32(id), nil
+}
+
+// File hands out consecutive IDs and records its position in a file, so
+// a new File on the same path continues where the last one stopped.
+//
+// IDs are reserved a block at a time: the file holds the first ID not yet
+// reserved and is rewritten once per block. IDs left in a block when the
+// process exits are skipped, never reused. File does not lock the path;
+// give each concurrently running process its own file.
+type File struct {
+	mu    sync.Mutex
+	path  string
+	block uint64
+	next  uint64 // next ID to hand out
+	limit uint64 // first ID past the reserved block
+}
+
+// OpenFile returns an allocator backed by path, creating the file if it
+// does not exist. A zero block uses DefaultFileBlock.
+func OpenFile(path string, block uint32) (*File, error) {
+	if block == 0 {
+		block = DefaultFileBlock
+	}
+
+	next, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{path: path, block: uint64(block), next: next, limit: next}
+	if err := f.reserve(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Next returns the next ID, reserving a new block when the current one
+// is used up.
+func (f *File) Next() (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.next >= f.limit {
+		if err := f.reserve(); err != nil {
+			return 0, err
+		}
+	}
+	id := f.next
+	f.next++
+	return uint32(id), nil
+}
+
+// Path returns the file the allocator persists to.
+func (f *File) Path() string {
+	return f.path
+}
+
+// reserve records a new block starting at f.next. Caller must hold f.mu
+// or own f exclusively.
+func (f *File) reserve() error {
+	if f.next > math.MaxUint32 {
+		return ErrExhausted
+	}
+
+	limit := f.next + f.block
+	if limit > math.MaxUint32+1 {
+		limit = math.MaxUint32 + 1
+	}
+	if err := writeFile(f.path, limit); err != nil {
+		return err
+	}
+	f.limit = limit
+	return nil
+}
+
+// readFile returns the first unreserved ID recorded at path, or 1 if the
+// file does not exist.
+func readFile(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	next, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || next == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidFile, path)
+	}
+	return next, nil
+}
+
+// writeFile atomically replaces path with next.
+func writeFile(path string, next uint64) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(strconv.FormatUint(next, 10) + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,149 @@
+// Full path: pkg/meclient/orderid/orderid_test.go
+
+package orderid
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMonotonic(t *testing.T) {
+	m := NewMonotonic(1000)
+
+	for want := uint32(1000); want < 1003; want++ {
+		if id, err := m.Next(); err != nil || id != want {
+			t.Fatalf("expected %d, got %d, %v", want, id, err)
+		}
+	}
+
+	if id, _ := NewMonotonic(0).Next(); id != 1 {
+		t.Errorf("expected zero start to begin at 1, got %d", id)
+	}
+}
+
+func TestMonotonic_Exhausted(t *testing.T) {
+	m := NewMonotonic(math.MaxUint32)
+
+	if id, err := m.Next(); err != nil || id != math.MaxUint32 {
+		t.Fatalf("expected max ID, got %d, %v", id, err)
+	}
+	if _, err := m.Next(); err != ErrExhausted {
+		t.Errorf("expected ErrExhausted, got %v", err)
+	}
+}
+
+func TestMonotonic_Concurrent(t *testing.T) {
+	m := NewMonotonic(1)
+
+	const workers, each = 8, 1000
+	ids := make(chan uint32, workers*each)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				id, _ := m.Next()
+				ids <- id
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[uint32]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate ID %d", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestTimeSeed(t *testing.T) {
+	epoch := time.UnixMilli(0)
+
+	if got := timeSeed(epoch); got != 1 {
+		t.Errorf("expected 1 at the epoch, got %d", got)
+	}
+	if got := timeSeed(epoch.Add(90 * time.Second)); got != 90001 {
+		t.Errorf("expected 90001, got %d", got)
+	}
+
+	// Days apart give different seeds; the seed repeats each period
+	day := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	if timeSeed(day) == timeSeed(day.Add(24*time.Hour)) {
+		t.Error("expected different seeds a day apart")
+	}
+	if got := timeSeed(epoch.Add(timeSeedPeriod*time.Millisecond - time.Millisecond)); got != timeSeedPeriod {
+		t.Errorf("expected %d at the end of the period, got %d", timeSeedPeriod, got)
+	}
+	if got := timeSeed(epoch.Add(timeSeedPeriod * time.Millisecond)); got != 1 {
+		t.Errorf("expected the seed to wrap to 1, got %d", got)
+	}
+}
+
+func TestFile_PersistsAcrossOpens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids")
+
+	f, err := OpenFile(path, 10)
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	for want := uint32(1); want <= 12; want++ {
+		if id, err := f.Next(); err != nil || id != want {
+			t.Fatalf("expected %d, got %d, %v", want, id, err)
+		}
+	}
+
+	// The second block (11-20) was reserved, so a new run starts at 21
+	f, err = OpenFile(path, 10)
+	if err != nil {
+		t.Fatalf("failed to reopen: %v", err)
+	}
+	if id, _ := f.Next(); id != 21 {
+		t.Errorf("expected 21 after reopen, got %d", id)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "31\n" {
+		t.Errorf("unexpected file contents %q", data)
+	}
+}
+
+func TestFile_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids")
+
+	for _, contents := range []string{"abc\n", "0\n", ""} {
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := OpenFile(path, 0); !errors.Is(err, ErrInvalidFile) {
+			t.Errorf("contents %q: expected ErrInvalidFile, got %v", contents, err)
+		}
+	}
+}
+
+func TestFile_Exhausted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids")
+	if err := os.WriteFile(path, []byte("4294967295\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenFile(path, 10)
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	if id, err := f.Next(); err != nil || id != math.MaxUint32 {
+		t.Fatalf("expected max ID, got %d, %v", id, err)
+	}
+	if _, err := f.Next(); err != ErrExhausted {
+		t.Errorf("expected ErrExhausted, got %v", err)
+	}
+}
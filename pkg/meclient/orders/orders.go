@@ -0,0 +1,506 @@
+// Full path: pkg/meclient/orders/orders.go
+
+// Package orders provides client-side order state tracking.
+//
+// A Manager records each order sent and follows it through the server's
+// responses: New → Acked → PartiallyFilled → Filled, or Cancelled or
+// Rejected. Orders open when the connection drops can be marked Unknown.
+// It is fed by the client's read loop and is safe for concurrent use.
+package orders
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+// State is the lifecycle state of a tracked order.
+type State uint8
+
+const (
+	StateNew             State = iota // Sent, not yet acknowledged
+	StateAcked                        // Resting on the book
+	StatePartiallyFilled              // Some quantity filled
+	StateFilled                       // Fully filled (terminal)
+	StateCancelled                    // Cancelled by request (terminal)
+	StateRejected                     // Refused by the server (terminal)
+	StateUnknown                      // Open when the connection dropped; may still be live
+)
+
+func (s State) String() string {
+	switch s {
+	case StateNew:
+		return "new"
+	case StateAcked:
+		return "acked"
+	case StatePartiallyFilled:
+		return "partially_filled"
+	case StateFilled:
+		return "filled"
+	case StateCancelled:
+		return "cancelled"
+	case StateRejected:
+		return "rejected"
+	case StateUnknown:
+		return "unknown_state"
+	default:
+		return "unknown"
+	}
+}
+
+// IsTerminal returns true if no further transitions are expected.
+func (s State) IsTerminal() bool {
+	return s == StateFilled || s == StateCancelled || s == StateRejected
+}
+
+// Errors
+var (
+	ErrDuplicateOrder = errors.New("order already tracked")
+)
+
+// Key identifies an order. Order IDs are scoped to a user.
+type Key struct {
+	UserID  uint32
+	OrderID uint32
+}
+
+// Fill is one execution against a tracked order.
+type Fill struct {
+	Price          uint32
+	Qty            uint32
+	CounterUserID  uint32
+	CounterOrderID uint32
+	Time           time.Time
+}
+
+// Order is a snapshot of a tracked order.
+type Order struct {
+	UserID    uint32
+	OrderID   uint32
+	Symbol    string
+	Side      protocol.Side
+	Price     uint32
+	Qty       uint32
+	FilledQty uint32
+	State     State
+	Reason    string // Reject reason, if the order or its cancel was rejected
+	Fills     []Fill
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	CancelPending bool // A cancel was sent and not yet answered
+}
+
+// Key returns the order's identity.
+func (o *Order) Key() Key {
+	return Key{UserID: o.UserID, OrderID: o.OrderID}
+}
+
+// Remaining returns the unfilled quantity.
+func (o *Order) Remaining() uint32 {
+	if o.FilledQty >= o.Qty {
+		return 0
+	}
+	return o.Qty - o.FilledQty
+}
+
+// IsOpen returns true if the order is not in a terminal state.
+func (o *Order) IsOpen() bool {
+	return !o.State.IsTerminal()
+}
+
+// clone returns a copy that shares no memory with o.
+func (o *Order) clone() Order {
+	c := *o
+	if o.Fills != nil {
+		c.Fills = append([]Fill(nil), o.Fills...)
+	}
+	return c
+}
+
+// Event reports a state change of a tracked order, or a refused cancel.
+type Event struct {
+	Order          Order // Snapshot after the change
+	Prev           State // State before the change
+	CancelRejected bool  // The order's cancel was rejected; the order is still open
+}
+
+// Manager tracks orders and their state.
+type Manager struct {
+	mu     sync.RWMutex
+	orders map[Key]*Order
+
+	// Finished orders, oldest first. Past terminalLimit the oldest are
+	// pruned.
+	terminal      []*Order
+	terminalLimit int
+
+	events  chan Event
+	dropped uint64
+	closed  bool
+
+	now func() time.Time
+}
+
+// NewManager creates a manager whose event channel holds buffer events.
+// It keeps at most terminalLimit filled, cancelled and rejected orders,
+// forgetting the oldest first; zero keeps them until PruneTerminal.
+func NewManager(buffer, terminalLimit int) *Manager {
+	return &Manager{
+		orders:        make(map[Key]*Order),
+		terminalLimit: terminalLimit,
+		events:        make(chan Event, buffer),
+		now:           time.Now,
+	}
+}
+
+// Events returns the state-change event channel. Events are dropped when
+// the channel is full; see Dropped.
+func (m *Manager) Events() <-chan Event {
+	return m.events
+}
+
+// Dropped returns the number of events dropped because Events was full.
+func (m *Manager) Dropped() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.dropped
+}
+
+// Close closes the event channel. Later updates are applied but not reported.
+func (m *Manager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.closed {
+		m.closed = true
+		close(m.events)
+	}
+}
+
+// Track starts tracking a newly sent order.
+// It returns ErrDuplicateOrder if an open order with the same key exists.
+func (m *Manager) Track(order protocol.NewOrder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := Key{UserID: order.UserID, OrderID: order.OrderID}
+	if existing, ok := m.orders[key]; ok && existing.IsOpen() {
+		return ErrDuplicateOrder
+	}
+
+	now := m.now()
+	o := &Order{
+		UserID:    order.UserID,
+		OrderID:   order.OrderID,
+		Symbol:    order.Symbol,
+		Side:      order.Side,
+		Price:     order.Price,
+		Qty:       order.Qty,
+		State:     StateNew,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	m.orders[key] = o
+	m.emit(o, StateNew)
+
+	return nil
+}
+
+// Untrack stops tracking an order, e.g. when it could not be sent.
+func (m *Manager) Untrack(userID, orderID uint32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.orders, Key{UserID: userID, OrderID: orderID})
+}
+
+// ApplyAck records an order acknowledgment.
+func (m *Manager) ApplyAck(ack protocol.Ack) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o, ok := m.orders[Key{UserID: ack.UserID, OrderID: ack.OrderID}]
+	if !ok || o.State != StateNew {
+		return
+	}
+	m.transition(o, StateAcked)
+}
+
+// ApplyTrade records a fill against either side of the trade.
+func (m *Manager) ApplyTrade(trade protocol.Trade) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+
+	if o, ok := m.orders[Key{UserID: trade.BuyUserID, OrderID: trade.BuyOrderID}]; ok {
+		m.fill(o, Fill{
+			Price:          trade.Price,
+			Qty:            trade.Qty,
+			CounterUserID:  trade.SellUserID,
+			CounterOrderID: trade.SellOrderID,
+			Time:           now,
+		})
+	}
+
+	if o, ok := m.orders[Key{UserID: trade.SellUserID, OrderID: trade.SellOrderID}]; ok {
+		m.fill(o, Fill{
+			Price:          trade.Price,
+			Qty:            trade.Qty,
+			CounterUserID:  trade.BuyUserID,
+			CounterOrderID: trade.BuyOrderID,
+			Time:           now,
+		})
+	}
+}
+
+// CancelSent records that a cancel was sent for an open order, so that a
+// reject naming the order is taken as refusing the cancel. It reports
+// whether the order was marked by this call, rather than already pending.
+func (m *Manager) CancelSent(userID, orderID uint32) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o, ok := m.orders[Key{UserID: userID, OrderID: orderID}]
+	if !ok || !o.IsOpen() || o.CancelPending {
+		return false
+	}
+	o.CancelPending = true
+	return true
+}
+
+// CancelUnsent undoes CancelSent for a cancel that never reached the
+// wire.
+func (m *Manager) CancelUnsent(userID, orderID uint32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if o, ok := m.orders[Key{UserID: userID, OrderID: orderID}]; ok && o.IsOpen() {
+		o.CancelPending = false
+	}
+}
+
+// ApplyCancelAck records a cancel acknowledgment.
+func (m *Manager) ApplyCancelAck(cancelAck protocol.CancelAck) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o, ok := m.orders[Key{UserID: cancelAck.UserID, OrderID: cancelAck.OrderID}]
+	if !ok || !o.IsOpen() {
+		return
+	}
+	m.transition(o, StateCancelled)
+}
+
+// ApplyReject records a server reject. The engine answers requests in
+// order, so a reject naming an order not yet acked rejects the order
+// itself. A reject naming a live order with a cancel pending rejects the
+// cancel: the order stays open and an event with CancelRejected set is
+// emitted. An order in StateUnknown with no cancel pending is taken as
+// rejected. Other rejects are ignored.
+func (m *Manager) ApplyReject(reject protocol.Reject) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o, ok := m.orders[Key{UserID: reject.UserID, OrderID: reject.OrderID}]
+	if !ok || !o.IsOpen() {
+		return
+	}
+
+	switch {
+	case o.State == StateNew:
+		o.Reason = reject.Reason
+		m.transition(o, StateRejected)
+	case o.CancelPending:
+		o.Reason = reject.Reason
+		o.CancelPending = false
+		o.UpdatedAt = m.now()
+		m.send(Event{Order: o.clone(), Prev: o.State, CancelRejected: true})
+	case o.State == StateUnknown:
+		o.Reason = reject.Reason
+		m.transition(o, StateRejected)
+	}
+}
+
+// Get returns a snapshot of the order with the given key.
+func (m *Manager) Get(userID, orderID uint32) (Order, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	o, ok := m.orders[Key{UserID: userID, OrderID: orderID}]
+	if !ok {
+		return Order{}, false
+	}
+	return o.clone(), true
+}
+
+// OpenOrders returns snapshots of all open orders, oldest first.
+func (m *Manager) OpenOrders() []Order {
+	return m.collect(func(o *Order) bool { return true })
+}
+
+// OpenOrdersBySymbol returns snapshots of open orders for symbol, oldest first.
+func (m *Manager) OpenOrdersBySymbol(symbol string) []Order {
+	return m.collect(func(o *Order) bool { return o.Symbol == symbol })
+}
+
+// OpenOrdersByUser returns snapshots of open orders for userID, oldest first.
+func (m *Manager) OpenOrdersByUser(userID uint32) []Order {
+	return m.collect(func(o *Order) bool { return o.UserID == userID })
+}
+
+// OpenCount returns the number of open orders.
+func (m *Manager) OpenCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	n := 0
+	for _, o := range m.orders {
+		if o.IsOpen() {
+			n++
+		}
+	}
+	return n
+}
+
+// MarkUnknown moves every open order to StateUnknown, e.g. after the
+// connection drops, and returns snapshots of them, oldest first. Later
+// fills and cancel acks still apply to them.
+func (m *Manager) MarkUnknown() []Order {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []Order
+	for _, o := range m.orders {
+		if !o.IsOpen() {
+			continue
+		}
+		if o.State != StateUnknown {
+			m.transition(o, StateUnknown)
+		}
+		result = append(result, o.clone())
+	}
+
+	sortOrders(result)
+	return result
+}
+
+// PruneTerminal stops tracking filled, cancelled and rejected orders and
+// returns how many were removed.
+func (m *Manager) PruneTerminal() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := 0
+	for key, o := range m.orders {
+		if !o.IsOpen() {
+			delete(m.orders, key)
+			n++
+		}
+	}
+	m.terminal = nil
+	return n
+}
+
+func (m *Manager) collect(match func(o *Order) bool) []Order {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []Order
+	for _, o := range m.orders {
+		if o.IsOpen() && match(o) {
+			result = append(result, o.clone())
+		}
+	}
+
+	sortOrders(result)
+	return result
+}
+
+// sortOrders sorts oldest first, breaking ties by key.
+func sortOrders(result []Order) {
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		if result[i].UserID != result[j].UserID {
+			return result[i].UserID < result[j].UserID
+		}
+		return result[i].OrderID < result[j].OrderID
+	})
+}
+
+// fill applies an execution. Caller must hold m.mu.
+func (m *Manager) fill(o *Order, f Fill) {
+	if !o.IsOpen() {
+		return
+	}
+
+	o.Fills = append(o.Fills, f)
+	o.FilledQty += f.Qty
+
+	if o.FilledQty >= o.Qty {
+		m.transition(o, StateFilled)
+	} else {
+		m.transition(o, StatePartiallyFilled)
+	}
+}
+
+// transition moves o to state and reports it. Caller must hold m.mu.
+func (m *Manager) transition(o *Order, state State) {
+	prev := o.State
+	o.State = state
+	o.UpdatedAt = m.now()
+	if state.IsTerminal() {
+		o.CancelPending = false
+		if !prev.IsTerminal() {
+			m.retire(o)
+		}
+	}
+	m.emit(o, prev)
+}
+
+// retire queues a newly finished order for pruning, forgetting the oldest
+// finished order once more than terminalLimit are kept. Caller must hold
+// m.mu.
+func (m *Manager) retire(o *Order) {
+	if m.terminalLimit <= 0 {
+		return
+	}
+
+	m.terminal = append(m.terminal, o)
+	if len(m.terminal) <= m.terminalLimit {
+		return
+	}
+
+	oldest := m.terminal[0]
+	m.terminal[0] = nil
+	m.terminal = m.terminal[1:]
+
+	// The key may since have been reused by a new order
+	if key := oldest.Key(); m.orders[key] == oldest {
+		delete(m.orders, key)
+	}
+}
+
+// emit sends a state-change event without blocking. Caller must hold m.mu.
+func (m *Manager) emit(o *Order, prev State) {
+	m.send(Event{Order: o.clone(), Prev: prev})
+}
+
+// send delivers ev without blocking. Caller must hold m.mu.
+func (m *Manager) send(ev Event) {
+	if m.closed {
+		return
+	}
+
+	select {
+	case m.events <- ev:
+	default:
+		m.dropped++
+	}
+}
@@ -0,0 +1,345 @@
+// Full path: pkg/meclient/orders/orders_test.go
+
+package orders
+
+import (
+	"testing"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+func newOrder(userID, orderID uint32, symbol string, side protocol.Side, qty uint32) protocol.NewOrder {
+	return protocol.NewOrder{
+		UserID:  userID,
+		OrderID: orderID,
+		Symbol:  symbol,
+		Side:    side,
+		Price:   100,
+		Qty:     qty,
+	}
+}
+
+func mustGet(t *testing.T, m *Manager, userID, orderID uint32) Order {
+	t.Helper()
+	o, ok := m.Get(userID, orderID)
+	if !ok {
+		t.Fatalf("order %d/%d not tracked", userID, orderID)
+	}
+	return o
+}
+
+func TestManager_Lifecycle(t *testing.T) {
+	m := NewManager(16, 0)
+
+	if err := m.Track(newOrder(1, 1001, "IBM", protocol.SideBuy, 100)); err != nil {
+		t.Fatalf("track error: %v", err)
+	}
+	if o := mustGet(t, m, 1, 1001); o.State != StateNew {
+		t.Errorf("expected new, got %v", o.State)
+	}
+
+	m.ApplyAck(protocol.Ack{Symbol: "IBM", UserID: 1, OrderID: 1001})
+	if o := mustGet(t, m, 1, 1001); o.State != StateAcked {
+		t.Errorf("expected acked, got %v", o.State)
+	}
+
+	m.ApplyTrade(protocol.Trade{Symbol: "IBM", BuyUserID: 1, BuyOrderID: 1001, SellUserID: 2, SellOrderID: 2001, Price: 100, Qty: 40})
+	o := mustGet(t, m, 1, 1001)
+	if o.State != StatePartiallyFilled || o.FilledQty != 40 || o.Remaining() != 60 {
+		t.Errorf("unexpected order after partial fill: %+v", o)
+	}
+
+	m.ApplyTrade(protocol.Trade{Symbol: "IBM", BuyUserID: 1, BuyOrderID: 1001, SellUserID: 3, SellOrderID: 3001, Price: 101, Qty: 60})
+	o = mustGet(t, m, 1, 1001)
+	if o.State != StateFilled || o.Remaining() != 0 {
+		t.Errorf("unexpected order after full fill: %+v", o)
+	}
+	if len(o.Fills) != 2 || o.Fills[1].CounterUserID != 3 || o.Fills[1].Price != 101 {
+		t.Errorf("unexpected fill history: %+v", o.Fills)
+	}
+
+	// Terminal orders ignore later updates
+	m.ApplyCancelAck(protocol.CancelAck{Symbol: "IBM", UserID: 1, OrderID: 1001})
+	if o := mustGet(t, m, 1, 1001); o.State != StateFilled {
+		t.Errorf("expected filled to stick, got %v", o.State)
+	}
+
+	want := []struct{ prev, state State }{
+		{StateNew, StateNew},
+		{StateNew, StateAcked},
+		{StateAcked, StatePartiallyFilled},
+		{StatePartiallyFilled, StateFilled},
+	}
+	for i, w := range want {
+		select {
+		case ev := <-m.Events():
+			if ev.Prev != w.prev || ev.Order.State != w.state {
+				t.Errorf("event %d: expected %v→%v, got %v→%v", i, w.prev, w.state, ev.Prev, ev.Order.State)
+			}
+		default:
+			t.Fatalf("event %d missing", i)
+		}
+	}
+}
+
+func TestManager_SellSideFill(t *testing.T) {
+	m := NewManager(16, 0)
+	_ = m.Track(newOrder(2, 2001, "IBM", protocol.SideSell, 10))
+
+	m.ApplyTrade(protocol.Trade{Symbol: "IBM", BuyUserID: 1, BuyOrderID: 1001, SellUserID: 2, SellOrderID: 2001, Price: 100, Qty: 10})
+
+	o := mustGet(t, m, 2, 2001)
+	if o.State != StateFilled || o.Fills[0].CounterOrderID != 1001 {
+		t.Errorf("unexpected order: %+v", o)
+	}
+}
+
+func TestManager_CancelAndReject(t *testing.T) {
+	m := NewManager(16, 0)
+	_ = m.Track(newOrder(1, 1, "IBM", protocol.SideBuy, 10))
+	_ = m.Track(newOrder(1, 2, "IBM", protocol.SideBuy, 10))
+
+	m.ApplyAck(protocol.Ack{UserID: 1, OrderID: 1})
+	m.ApplyCancelAck(protocol.CancelAck{UserID: 1, OrderID: 1})
+	m.ApplyReject(protocol.Reject{UserID: 1, OrderID: 2, Reason: "price outside collar"})
+
+	if o := mustGet(t, m, 1, 1); o.State != StateCancelled {
+		t.Errorf("expected cancelled, got %v", o.State)
+	}
+	o := mustGet(t, m, 1, 2)
+	if o.State != StateRejected || o.Reason != "price outside collar" {
+		t.Errorf("unexpected rejected order: %+v", o)
+	}
+	if n := m.OpenCount(); n != 0 {
+		t.Errorf("expected no open orders, got %d", n)
+	}
+}
+
+func TestManager_CancelRejected(t *testing.T) {
+	m := NewManager(16, 0)
+	_ = m.Track(newOrder(1, 1, "IBM", protocol.SideBuy, 10))
+	_ = m.Track(newOrder(1, 2, "IBM", protocol.SideBuy, 10))
+	m.ApplyAck(protocol.Ack{UserID: 1, OrderID: 1})
+	m.ApplyAck(protocol.Ack{UserID: 1, OrderID: 2})
+	for len(m.Events()) > 0 {
+		<-m.Events()
+	}
+
+	// A reject of a live order with no cancel pending is not ours to apply
+	m.ApplyReject(protocol.Reject{UserID: 1, OrderID: 2, Reason: "stray"})
+	if o := mustGet(t, m, 1, 2); o.State != StateAcked || o.Reason != "" {
+		t.Errorf("expected stray reject ignored, got %+v", o)
+	}
+
+	if !m.CancelSent(1, 1) {
+		t.Error("expected the cancel to mark the order")
+	}
+	m.CancelUnsent(1, 1)
+	if o := mustGet(t, m, 1, 1); o.CancelPending {
+		t.Error("expected an unsent cancel to clear pending")
+	}
+
+	m.CancelSent(1, 1)
+	if m.CancelSent(1, 1) {
+		t.Error("expected a second cancel not to mark the order again")
+	}
+	if o := mustGet(t, m, 1, 1); !o.CancelPending {
+		t.Error("expected cancel pending")
+	}
+	m.ApplyReject(protocol.Reject{UserID: 1, OrderID: 1, Reason: "cancel refused"})
+
+	o := mustGet(t, m, 1, 1)
+	if o.State != StateAcked || o.CancelPending || o.Reason != "cancel refused" {
+		t.Errorf("expected order still live after cancel reject, got %+v", o)
+	}
+	if n := m.OpenCount(); n != 2 {
+		t.Errorf("expected 2 open orders, got %d", n)
+	}
+	select {
+	case ev := <-m.Events():
+		if !ev.CancelRejected || ev.Prev != StateAcked || ev.Order.OrderID != 1 {
+			t.Errorf("unexpected event: %+v", ev)
+		}
+	default:
+		t.Error("expected a cancel-rejected event")
+	}
+
+	// A reject of an order not yet acked is the order's, even with a
+	// cancel behind it
+	_ = m.Track(newOrder(1, 3, "IBM", protocol.SideBuy, 10))
+	m.CancelSent(1, 3)
+	m.ApplyReject(protocol.Reject{UserID: 1, OrderID: 3, Reason: "price outside collar"})
+	if o := mustGet(t, m, 1, 3); o.State != StateRejected || o.CancelPending {
+		t.Errorf("expected order rejected, got %+v", o)
+	}
+}
+
+func TestManager_OpenOrdersFilters(t *testing.T) {
+	m := NewManager(16, 0)
+	_ = m.Track(newOrder(1, 1, "IBM", protocol.SideBuy, 10))
+	_ = m.Track(newOrder(1, 2, "AAPL", protocol.SideBuy, 10))
+	_ = m.Track(newOrder(2, 1, "IBM", protocol.SideSell, 10))
+	_ = m.Track(newOrder(2, 2, "IBM", protocol.SideSell, 10))
+	m.ApplyCancelAck(protocol.CancelAck{UserID: 2, OrderID: 2})
+
+	if got := len(m.OpenOrders()); got != 3 {
+		t.Errorf("expected 3 open orders, got %d", got)
+	}
+
+	ibm := m.OpenOrdersBySymbol("IBM")
+	if len(ibm) != 2 || ibm[0].Key() != (Key{1, 1}) || ibm[1].Key() != (Key{2, 1}) {
+		t.Errorf("unexpected IBM orders: %+v", ibm)
+	}
+
+	user1 := m.OpenOrdersByUser(1)
+	if len(user1) != 2 {
+		t.Errorf("expected 2 orders for user 1, got %d", len(user1))
+	}
+}
+
+func TestManager_DuplicateAndUntrack(t *testing.T) {
+	m := NewManager(16, 0)
+	order := newOrder(1, 1, "IBM", protocol.SideBuy, 10)
+
+	if err := m.Track(order); err != nil {
+		t.Fatalf("track error: %v", err)
+	}
+	if err := m.Track(order); err != ErrDuplicateOrder {
+		t.Errorf("expected ErrDuplicateOrder, got %v", err)
+	}
+
+	m.Untrack(1, 1)
+	if _, ok := m.Get(1, 1); ok {
+		t.Error("expected order to be untracked")
+	}
+	if err := m.Track(order); err != nil {
+		t.Errorf("expected re-track after untrack, got %v", err)
+	}
+}
+
+func TestManager_PruneTerminal(t *testing.T) {
+	m := NewManager(16, 0)
+	_ = m.Track(newOrder(1, 1, "IBM", protocol.SideBuy, 10))
+	_ = m.Track(newOrder(1, 2, "IBM", protocol.SideBuy, 10))
+	m.ApplyCancelAck(protocol.CancelAck{UserID: 1, OrderID: 1})
+
+	if n := m.PruneTerminal(); n != 1 {
+		t.Errorf("expected 1 pruned, got %d", n)
+	}
+	if _, ok := m.Get(1, 1); ok {
+		t.Error("expected cancelled order to be pruned")
+	}
+	if _, ok := m.Get(1, 2); !ok {
+		t.Error("expected open order to remain")
+	}
+}
+
+func TestManager_TerminalLimit(t *testing.T) {
+	m := NewManager(16, 2)
+	for id := uint32(1); id <= 4; id++ {
+		_ = m.Track(newOrder(1, id, "IBM", protocol.SideBuy, 10))
+	}
+
+	// Finishing a third order forgets the oldest finished one
+	m.ApplyCancelAck(protocol.CancelAck{UserID: 1, OrderID: 1})
+	m.ApplyReject(protocol.Reject{UserID: 1, OrderID: 2, Reason: "halted"})
+	m.ApplyCancelAck(protocol.CancelAck{UserID: 1, OrderID: 3})
+
+	if _, ok := m.Get(1, 1); ok {
+		t.Error("expected the oldest finished order to be pruned")
+	}
+	for _, id := range []uint32{2, 3, 4} {
+		if _, ok := m.Get(1, id); !ok {
+			t.Errorf("expected order %d to be kept", id)
+		}
+	}
+
+	// A reused key is not pruned along with the order it replaced
+	_ = m.Track(newOrder(1, 2, "IBM", protocol.SideBuy, 10))
+	m.ApplyCancelAck(protocol.CancelAck{UserID: 1, OrderID: 4})
+	if o, ok := m.Get(1, 2); !ok || !o.IsOpen() {
+		t.Errorf("expected re-tracked order 2 to be kept open, got %+v", o)
+	}
+}
+
+func TestManager_MarkUnknown(t *testing.T) {
+	m := NewManager(16, 0)
+	_ = m.Track(newOrder(1, 1, "IBM", protocol.SideBuy, 10))
+	_ = m.Track(newOrder(1, 2, "IBM", protocol.SideBuy, 10))
+	_ = m.Track(newOrder(1, 3, "IBM", protocol.SideBuy, 10))
+	m.ApplyAck(protocol.Ack{UserID: 1, OrderID: 1})
+	m.ApplyCancelAck(protocol.CancelAck{UserID: 1, OrderID: 3})
+
+	marked := m.MarkUnknown()
+	if len(marked) != 2 || marked[0].OrderID != 1 || marked[1].OrderID != 2 {
+		t.Fatalf("unexpected marked orders: %+v", marked)
+	}
+	for _, o := range marked {
+		if o.State != StateUnknown || !o.IsOpen() {
+			t.Errorf("expected open unknown order, got %+v", o)
+		}
+	}
+	if o := mustGet(t, m, 1, 3); o.State != StateCancelled {
+		t.Errorf("expected terminal order untouched, got %v", o.State)
+	}
+
+	// Responses after the reconnect still resolve unknown orders
+	m.ApplyCancelAck(protocol.CancelAck{UserID: 1, OrderID: 1})
+	m.ApplyTrade(protocol.Trade{BuyUserID: 1, BuyOrderID: 2, SellUserID: 2, SellOrderID: 1, Price: 100, Qty: 10})
+	if o := mustGet(t, m, 1, 1); o.State != StateCancelled {
+		t.Errorf("expected cancelled, got %v", o.State)
+	}
+	if o := mustGet(t, m, 1, 2); o.State != StateFilled {
+		t.Errorf("expected filled, got %v", o.State)
+	}
+	if n := len(m.MarkUnknown()); n != 0 {
+		t.Errorf("expected nothing left to mark, got %d", n)
+	}
+}
+
+func TestManager_SnapshotIsolation(t *testing.T) {
+	m := NewManager(16, 0)
+	_ = m.Track(newOrder(1, 1, "IBM", protocol.SideBuy, 10))
+	m.ApplyTrade(protocol.Trade{BuyUserID: 1, BuyOrderID: 1, Qty: 5})
+
+	o := mustGet(t, m, 1, 1)
+	o.Fills[0].Qty = 999
+
+	if again := mustGet(t, m, 1, 1); again.Fills[0].Qty != 5 {
+		t.Error("snapshot shares fill history with manager")
+	}
+}
+
+func TestManager_EventsDroppedWhenFull(t *testing.T) {
+	m := NewManager(1, 0)
+	_ = m.Track(newOrder(1, 1, "IBM", protocol.SideBuy, 10))
+	m.ApplyAck(protocol.Ack{UserID: 1, OrderID: 1})
+
+	if n := m.Dropped(); n != 1 {
+		t.Errorf("expected 1 dropped event, got %d", n)
+	}
+
+	m.Close()
+	m.ApplyCancelAck(protocol.CancelAck{UserID: 1, OrderID: 1})
+	if o := mustGet(t, m, 1, 1); o.State != StateCancelled {
+		t.Errorf("expected updates after close, got %v", o.State)
+	}
+}
+
+func TestStateString(t *testing.T) {
+	tests := map[State]string{
+		StateNew:             "new",
+		StateAcked:           "acked",
+		StatePartiallyFilled: "partially_filled",
+		StateFilled:          "filled",
+		StateCancelled:       "cancelled",
+		StateRejected:        "rejected",
+		StateUnknown:         "unknown_state",
+		State(99):            "unknown",
+	}
+	for state, want := range tests {
+		if got := state.String(); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+}
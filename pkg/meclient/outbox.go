@@ -0,0 +1,183 @@
+// Full path: pkg/meclient/outbox.go
+
+package meclient
+
+import (
+	"sync"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/config"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/internal/stats"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+// outbox is an inbound delivery channel with a backpressure policy.
+// deliver is called only from the read loop.
+type outbox[T any] struct {
+	ch     chan T
+	policy config.Backpressure
+	client *Client
+
+	// Spill state. While spill is non-empty every new message is appended
+	// behind it so delivery order is preserved. Past spillLimit messages
+	// new ones are dropped.
+	mu         sync.Mutex
+	spill      []T
+	spillLimit int
+	pumping    bool
+}
+
+func newOutbox[T any](c *Client, size int, policy config.Backpressure) *outbox[T] {
+	return &outbox[T]{
+		ch:         make(chan T, size),
+		policy:     policy,
+		client:     c,
+		spillLimit: c.cfg.SpillLimit,
+	}
+}
+
+// deliver hands v to the consumer according to the policy.
+func (o *outbox[T]) deliver(v T) {
+	if o.policy == config.BackpressureSpill {
+		o.deliverSpill(v)
+		return
+	}
+
+	select {
+	case o.ch <- v:
+		return
+	default:
+	}
+
+	st := &o.client.stats
+
+	switch o.policy {
+	case config.BackpressureDropOldest:
+		for {
+			select {
+			case old := <-o.ch:
+				st.IncDroppedOldest()
+				st.IncDroppedMessages()
+				o.countDropped(old)
+			default:
+			}
+			select {
+			case o.ch <- v:
+				return
+			default:
+			}
+		}
+
+	case config.BackpressureBlock:
+		st.IncBlockedSends()
+		select {
+		case o.ch <- v:
+		case <-o.client.ctx.Done():
+		}
+
+	default:
+		o.dropNewest(v)
+	}
+}
+
+// dropNewest discards v and reports the channel full.
+func (o *outbox[T]) dropNewest(v T) {
+	st := &o.client.stats
+	st.IncDroppedNewest()
+	st.IncDroppedMessages()
+	o.countDropped(v)
+	o.client.sendError(ErrChannelFull)
+}
+
+// countDropped records a dropped message against its symbol.
+func (o *outbox[T]) countDropped(v T) {
+	if o.client.cfg.TrackSymbolStats {
+		o.client.countSymbol(messageSymbol(v), stats.SymbolDropped)
+	}
+}
+
+// messageSymbol returns the symbol of a delivered message, or "" if it
+// has none.
+func messageSymbol(v any) string {
+	switch v := v.(type) {
+	case protocol.Ack:
+		return v.Symbol
+	case protocol.Trade:
+		return v.Symbol
+	case protocol.BookUpdate:
+		return v.Symbol
+	case protocol.CancelAck:
+		return v.Symbol
+	case protocol.Reject:
+		return v.Symbol
+	case Event:
+		switch v.Kind {
+		case EventAck:
+			return v.Ack.Symbol
+		case EventTrade:
+			return v.Trade.Symbol
+		case EventBookUpdate:
+			return v.BookUpdate.Symbol
+		case EventCancelAck:
+			return v.CancelAck.Symbol
+		case EventReject:
+			return v.Reject.Symbol
+		}
+	}
+	return ""
+}
+
+func (o *outbox[T]) deliverSpill(v T) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.spill) == 0 {
+		select {
+		case o.ch <- v:
+			return
+		default:
+		}
+	}
+	if len(o.spill) >= o.spillLimit {
+		o.dropNewest(v)
+		return
+	}
+
+	o.spill = append(o.spill, v)
+	o.client.stats.IncSpilled()
+
+	if !o.pumping {
+		o.pumping = true
+		o.client.wg.Add(1)
+		go o.pump()
+	}
+}
+
+// pump moves spilled messages to the channel until the spill is empty.
+func (o *outbox[T]) pump() {
+	defer o.client.wg.Done()
+
+	ctx := o.client.ctx
+	for {
+		o.mu.Lock()
+		if len(o.spill) == 0 {
+			o.spill = nil
+			o.pumping = false
+			o.mu.Unlock()
+			return
+		}
+		v := o.spill[0]
+		o.mu.Unlock()
+
+		select {
+		case o.ch <- v:
+		case <-ctx.Done():
+			return
+		}
+
+		o.mu.Lock()
+		var zero T
+		o.spill[0] = zero
+		o.spill = o.spill[1:]
+		o.mu.Unlock()
+	}
+}
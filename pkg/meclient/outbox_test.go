@@ -0,0 +1,189 @@
+// Full path: pkg/meclient/outbox_test.go
+
+package meclient
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestOutbox(t *testing.T, policy Backpressure) (*Client, *outbox[int]) {
+	t.Helper()
+
+	client, err := New(DefaultConfig("localhost:1234"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client, newOutbox[int](client, 2, policy)
+}
+
+func drain(o *outbox[int], n int, timeout time.Duration) []int {
+	var got []int
+	deadline := time.After(timeout)
+	for len(got) < n {
+		select {
+		case v := <-o.ch:
+			got = append(got, v)
+		case <-deadline:
+			return got
+		}
+	}
+	return got
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestOutbox_DropNewest(t *testing.T) {
+	client, o := newTestOutbox(t, BackpressureDropNewest)
+
+	for i := 1; i <= 3; i++ {
+		o.deliver(i)
+	}
+
+	if got := drain(o, 2, time.Second); !equalInts(got, []int{1, 2}) {
+		t.Errorf("expected [1 2], got %v", got)
+	}
+	if n := client.Stats().DroppedNewest; n != 1 {
+		t.Errorf("expected 1 dropped-newest, got %d", n)
+	}
+}
+
+func TestOutbox_DropOldest(t *testing.T) {
+	client, o := newTestOutbox(t, BackpressureDropOldest)
+
+	for i := 1; i <= 4; i++ {
+		o.deliver(i)
+	}
+
+	if got := drain(o, 2, time.Second); !equalInts(got, []int{3, 4}) {
+		t.Errorf("expected [3 4], got %v", got)
+	}
+	snap := client.Stats()
+	if snap.DroppedOldest != 2 || snap.DroppedMessages != 2 {
+		t.Errorf("expected 2 dropped-oldest, got %+v", snap)
+	}
+}
+
+func TestOutbox_Block(t *testing.T) {
+	client, o := newTestOutbox(t, BackpressureBlock)
+
+	o.deliver(1)
+	o.deliver(2)
+
+	delivered := make(chan struct{})
+	go func() {
+		o.deliver(3)
+		close(delivered)
+	}()
+
+	select {
+	case <-delivered:
+		t.Fatal("deliver should block while the channel is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if got := drain(o, 3, time.Second); !equalInts(got, []int{1, 2, 3}) {
+		t.Errorf("expected [1 2 3], got %v", got)
+	}
+	<-delivered
+
+	if n := client.Stats().BlockedSends; n != 1 {
+		t.Errorf("expected 1 blocked send, got %d", n)
+	}
+}
+
+func TestOutbox_BlockReleasedOnClose(t *testing.T) {
+	client, o := newTestOutbox(t, BackpressureBlock)
+
+	o.deliver(1)
+	o.deliver(2)
+
+	delivered := make(chan struct{})
+	go func() {
+		o.deliver(3)
+		close(delivered)
+	}()
+
+	client.cancel()
+
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("deliver still blocked after close")
+	}
+}
+
+func TestOutbox_Spill(t *testing.T) {
+	client, o := newTestOutbox(t, BackpressureSpill)
+
+	want := make([]int, 10)
+	for i := range want {
+		want[i] = i + 1
+		o.deliver(i + 1)
+	}
+
+	if got := drain(o, len(want), 2*time.Second); !equalInts(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	snap := client.Stats()
+	if snap.Spilled != 8 || snap.DroppedMessages != 0 {
+		t.Errorf("expected 8 spilled and none dropped, got %+v", snap)
+	}
+
+	// The pump exits once the spill is empty
+	deadline := time.Now().Add(time.Second)
+	for {
+		o.mu.Lock()
+		pumping := o.pumping
+		o.mu.Unlock()
+		if !pumping {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("pump still running after spill drained")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestOutbox_SpillLimit(t *testing.T) {
+	client, o := newTestOutbox(t, BackpressureSpill)
+	o.spillLimit = 3
+
+	// Two fit the channel and three the spill; the rest are dropped
+	for i := 1; i <= 10; i++ {
+		o.deliver(i)
+	}
+
+	if got, want := drain(o, 5, 2*time.Second), []int{1, 2, 3, 4, 5}; !equalInts(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	snap := client.Stats()
+	if snap.Spilled != 3 || snap.DroppedNewest != 5 || snap.DroppedMessages != 5 {
+		t.Errorf("expected 3 spilled and 5 dropped, got %+v", snap)
+	}
+}
+
+func TestClient_BackpressureDefaults(t *testing.T) {
+	client, _ := New(DefaultConfig("localhost:1234"))
+	defer client.Close()
+
+	if client.trades.policy != BackpressureSpill || client.events.policy != BackpressureSpill {
+		t.Error("trades and events should spill by default")
+	}
+	if client.acks.policy != BackpressureDropNewest || client.bookUpdates.policy != BackpressureDropNewest {
+		t.Error("acks and book updates should drop newest by default")
+	}
+}
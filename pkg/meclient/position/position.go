@@ -0,0 +1,245 @@
+// Full path: pkg/meclient/position/position.go
+
+// Package position keeps per-symbol positions and P&L from trade fills.
+//
+// A Keeper knows which user IDs are ours and applies the side of each
+// trade those users took part in; a trade between two of our users is
+// applied to both sides and nets out. Cost is tracked as a running
+// average: adding to a position moves the average, reducing it realizes
+// P&L against the average. Open positions are marked to the mid of the
+// latest best bid and offer, or to the last trade price when the book is
+// one-sided or empty. Keeper is safe for concurrent use.
+package position
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+// Position is a snapshot of our holdings in one symbol. P&L is in price
+// units times quantity.
+type Position struct {
+	Symbol     string
+	Net        int64   // Positive is long, negative is short
+	AvgCost    float64 // Average price of the open position; 0 when flat
+	Realized   float64
+	Unrealized float64 // (Mark - AvgCost) × Net; 0 without a mark
+	Mark       float64 // Price the position is marked at; 0 if none yet
+	BoughtQty  uint64
+	SoldQty    uint64
+	Fills      uint64
+}
+
+// PnL returns realized plus unrealized P&L.
+func (p *Position) PnL() float64 {
+	return p.Realized + p.Unrealized
+}
+
+// quote is the latest top of book and trade price for a symbol.
+type quote struct {
+	bid, ask  uint32 // 0 when the side is empty
+	lastTrade uint32
+}
+
+// mark returns the mid if the book is two-sided, else the last trade.
+func (q *quote) mark() float64 {
+	if q.bid != 0 && q.ask != 0 {
+		return (float64(q.bid) + float64(q.ask)) / 2
+	}
+	return float64(q.lastTrade)
+}
+
+// Keeper aggregates our fills into positions.
+type Keeper struct {
+	mu        sync.RWMutex
+	users     map[uint32]struct{}
+	positions map[string]*Position
+	quotes    map[string]*quote
+}
+
+// NewKeeper creates a keeper that attributes fills to userIDs. More can
+// be added with AddUser.
+func NewKeeper(userIDs ...uint32) *Keeper {
+	k := &Keeper{
+		users:     make(map[uint32]struct{}),
+		positions: make(map[string]*Position),
+		quotes:    make(map[string]*quote),
+	}
+	for _, id := range userIDs {
+		k.users[id] = struct{}{}
+	}
+	return k
+}
+
+// AddUser marks userID as ours. Fills already seen are not revisited.
+func (k *Keeper) AddUser(userID uint32) {
+	k.mu.RLock()
+	_, ok := k.users[userID]
+	k.mu.RUnlock()
+	if ok {
+		return
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.users[userID] = struct{}{}
+}
+
+// ApplyTrade applies our side or sides of trade and updates the mark.
+func (k *Keeper) ApplyTrade(trade protocol.Trade) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.quote(trade.Symbol).lastTrade = trade.Price
+
+	if _, ok := k.users[trade.BuyUserID]; ok {
+		k.fill(trade.Symbol, int64(trade.Qty), trade.Price)
+	}
+	if _, ok := k.users[trade.SellUserID]; ok {
+		k.fill(trade.Symbol, -int64(trade.Qty), trade.Price)
+	}
+	if p, ok := k.positions[trade.Symbol]; ok {
+		k.markTo(p)
+	}
+}
+
+// ApplyBookUpdate records the best price on one side and re-marks the
+// symbol's position. A zero quantity empties the side.
+func (k *Keeper) ApplyBookUpdate(update protocol.BookUpdate) {
+	price := update.Price
+	if update.Qty == 0 {
+		price = 0
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	q := k.quote(update.Symbol)
+	switch update.Side {
+	case protocol.SideBuy:
+		q.bid = price
+	case protocol.SideSell:
+		q.ask = price
+	default:
+		return
+	}
+	if p, ok := k.positions[update.Symbol]; ok {
+		k.markTo(p)
+	}
+}
+
+// Position returns the position in symbol.
+func (k *Keeper) Position(symbol string) (Position, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	p, ok := k.positions[symbol]
+	if !ok {
+		return Position{}, false
+	}
+	return *p, true
+}
+
+// Positions returns every symbol traded, sorted by symbol.
+func (k *Keeper) Positions() []Position {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	positions := make([]Position, 0, len(k.positions))
+	for _, p := range k.positions {
+		positions = append(positions, *p)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Symbol < positions[j].Symbol
+	})
+	return positions
+}
+
+// PnL returns realized and unrealized P&L summed over all symbols.
+func (k *Keeper) PnL() (realized, unrealized float64) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, p := range k.positions {
+		realized += p.Realized
+		unrealized += p.Unrealized
+	}
+	return realized, unrealized
+}
+
+// Reset forgets all positions and marks. Users are kept.
+func (k *Keeper) Reset() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.positions = make(map[string]*Position)
+	k.quotes = make(map[string]*quote)
+}
+
+// fill applies a signed quantity at price. Caller must hold k.mu.
+func (k *Keeper) fill(symbol string, qty int64, price uint32) {
+	p, ok := k.positions[symbol]
+	if !ok {
+		p = &Position{Symbol: symbol}
+		k.positions[symbol] = p
+	}
+
+	p.Fills++
+	if qty > 0 {
+		p.BoughtQty += uint64(qty)
+	} else {
+		p.SoldQty += uint64(-qty)
+	}
+
+	px := float64(price)
+	switch {
+	case p.Net == 0 || (p.Net > 0) == (qty > 0):
+		// Opening or adding: move the average
+		total := abs(p.Net) + abs(qty)
+		p.AvgCost = (p.AvgCost*float64(abs(p.Net)) + px*float64(abs(qty))) / float64(total)
+		p.Net += qty
+
+	case abs(qty) <= abs(p.Net):
+		// Reducing: realize against the average
+		p.Realized += (px - p.AvgCost) * float64(-qty)
+		p.Net += qty
+		if p.Net == 0 {
+			p.AvgCost = 0
+		}
+
+	default:
+		// Flipping: close the whole position, open the rest at price
+		p.Realized += (px - p.AvgCost) * float64(p.Net)
+		p.Net += qty
+		p.AvgCost = px
+	}
+}
+
+// markTo updates p's mark and unrealized P&L. Caller must hold k.mu.
+func (k *Keeper) markTo(p *Position) {
+	p.Mark = k.quote(p.Symbol).mark()
+	if p.Mark == 0 || p.Net == 0 {
+		p.Unrealized = 0
+		return
+	}
+	p.Unrealized = (p.Mark - p.AvgCost) * float64(p.Net)
+}
+
+// quote returns symbol's quote, creating it. Caller must hold k.mu.
+func (k *Keeper) quote(symbol string) *quote {
+	q, ok := k.quotes[symbol]
+	if !ok {
+		q = &quote{}
+		k.quotes[symbol] = q
+	}
+	return q
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
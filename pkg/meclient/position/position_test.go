@@ -0,0 +1,130 @@
+// Full path: pkg/meclient/position/position_test.go
+
+package position
+
+import (
+	"testing"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+// buy returns a trade where user 1 buys from user 2.
+func buy(symbol string, price, qty uint32) protocol.Trade {
+	return protocol.Trade{Symbol: symbol, BuyUserID: 1, SellUserID: 2, Price: price, Qty: qty}
+}
+
+// sell returns a trade where user 1 sells to user 2.
+func sell(symbol string, price, qty uint32) protocol.Trade {
+	return protocol.Trade{Symbol: symbol, BuyUserID: 2, SellUserID: 1, Price: price, Qty: qty}
+}
+
+func expectPosition(t *testing.T, k *Keeper, symbol string, net int64, avg, realized, unrealized float64) {
+	t.Helper()
+
+	p, ok := k.Position(symbol)
+	if !ok {
+		t.Fatalf("expected %s position", symbol)
+	}
+	if p.Net != net || p.AvgCost != avg || p.Realized != realized || p.Unrealized != unrealized {
+		t.Errorf("expected net=%d avg=%v realized=%v unrealized=%v, got %+v",
+			net, avg, realized, unrealized, p)
+	}
+}
+
+func TestKeeper_AverageCost(t *testing.T) {
+	k := NewKeeper(1)
+
+	k.ApplyTrade(buy("IBM", 100, 10))
+	k.ApplyTrade(buy("IBM", 110, 30))
+	// Marked to the last trade: (110 - 107.5) × 40
+	expectPosition(t, k, "IBM", 40, 107.5, 0, 100)
+
+	k.ApplyTrade(sell("IBM", 120, 20))
+	expectPosition(t, k, "IBM", 20, 107.5, 250, 250)
+
+	k.ApplyTrade(sell("IBM", 100, 20))
+	expectPosition(t, k, "IBM", 0, 0, 100, 0)
+
+	p, _ := k.Position("IBM")
+	if p.BoughtQty != 40 || p.SoldQty != 40 || p.Fills != 4 {
+		t.Errorf("unexpected totals: %+v", p)
+	}
+}
+
+func TestKeeper_Short(t *testing.T) {
+	k := NewKeeper(1)
+
+	k.ApplyTrade(sell("IBM", 100, 10))
+	expectPosition(t, k, "IBM", -10, 100, 0, 0)
+
+	k.ApplyTrade(buy("IBM", 90, 4))
+	expectPosition(t, k, "IBM", -6, 100, 40, 60)
+}
+
+func TestKeeper_Flip(t *testing.T) {
+	k := NewKeeper(1)
+
+	k.ApplyTrade(buy("IBM", 100, 10))
+	k.ApplyTrade(sell("IBM", 105, 15))
+	// 10 closed at +5 each, 5 short opened at 105
+	expectPosition(t, k, "IBM", -5, 105, 50, 0)
+}
+
+func TestKeeper_MarkToBook(t *testing.T) {
+	k := NewKeeper(1)
+
+	k.ApplyTrade(buy("IBM", 100, 10))
+	k.ApplyBookUpdate(protocol.BookUpdate{Symbol: "IBM", Side: protocol.SideBuy, Price: 102, Qty: 5})
+	// One-sided book: still marked to the last trade
+	expectPosition(t, k, "IBM", 10, 100, 0, 0)
+
+	k.ApplyBookUpdate(protocol.BookUpdate{Symbol: "IBM", Side: protocol.SideSell, Price: 106, Qty: 5})
+	expectPosition(t, k, "IBM", 10, 100, 0, 40)
+
+	// "-,-" empties the ask, falling back to the last trade
+	k.ApplyBookUpdate(protocol.BookUpdate{Symbol: "IBM", Side: protocol.SideSell})
+	expectPosition(t, k, "IBM", 10, 100, 0, 0)
+}
+
+func TestKeeper_Attribution(t *testing.T) {
+	k := NewKeeper(1)
+
+	// Neither side is ours
+	k.ApplyTrade(protocol.Trade{Symbol: "AAPL", BuyUserID: 5, SellUserID: 6, Price: 175, Qty: 10})
+	if _, ok := k.Position("AAPL"); ok {
+		t.Error("expected no position from others' trades")
+	}
+
+	// Both sides ours nets out
+	k.AddUser(2)
+	k.ApplyTrade(buy("IBM", 100, 10))
+	expectPosition(t, k, "IBM", 0, 0, 0, 0)
+
+	k.ApplyTrade(protocol.Trade{Symbol: "MSFT", BuyUserID: 2, SellUserID: 9, Price: 300, Qty: 1})
+	positions := k.Positions()
+	if len(positions) != 2 || positions[0].Symbol != "IBM" || positions[1].Symbol != "MSFT" {
+		t.Errorf("unexpected positions: %+v", positions)
+	}
+}
+
+func TestKeeper_PnLAndReset(t *testing.T) {
+	k := NewKeeper(1)
+
+	k.ApplyTrade(buy("IBM", 100, 10))
+	k.ApplyTrade(sell("IBM", 110, 5))
+	k.ApplyTrade(sell("AAPL", 200, 1))
+	k.ApplyTrade(protocol.Trade{Symbol: "AAPL", BuyUserID: 3, SellUserID: 4, Price: 190, Qty: 1})
+
+	realized, unrealized := k.PnL()
+	if realized != 50 || unrealized != 60 {
+		t.Errorf("expected realized 50, unrealized 60, got %v, %v", realized, unrealized)
+	}
+	if p, _ := k.Position("IBM"); p.PnL() != 100 {
+		t.Errorf("expected IBM P&L 100, got %v", p.PnL())
+	}
+
+	k.Reset()
+	if n := len(k.Positions()); n != 0 {
+		t.Errorf("expected no positions after reset, got %d", n)
+	}
+}
@@ -4,6 +4,9 @@ package protocol
 
 import (
 	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
 	"testing"
 )
 
@@ -26,6 +29,63 @@ func BenchmarkEncodeNewOrder(b *testing.B) {
 	}
 }
 
+// sprintfEncodeNewOrder is the previous encoder implementation, kept as a
+// baseline: fmt.Sprintf payload plus separate writes for length and payload.
+func sprintfEncodeNewOrder(w io.Writer, lenBuf *[4]byte, order *NewOrder) error {
+	payload := fmt.Sprintf("N,%d,%s,%d,%d,%c,%d\n",
+		order.UserID,
+		order.Symbol,
+		order.Price,
+		order.Qty,
+		order.Side,
+		order.OrderID)
+
+	binary.BigEndian.PutUint32(lenBuf[:], uint32(len(payload)))
+	if _, err := w.Write(lenBuf[:]); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte(payload))
+	return err
+}
+
+func BenchmarkEncodeNewOrder_SprintfBaseline(b *testing.B) {
+	var buf bytes.Buffer
+	var lenBuf [4]byte
+	order := &NewOrder{
+		UserID:  1,
+		Symbol:  "IBM",
+		Price:   100,
+		Qty:     50,
+		Side:    SideBuy,
+		OrderID: 1,
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		_ = sprintfEncodeNewOrder(&buf, &lenBuf, order)
+	}
+}
+
+func BenchmarkEncodeNewOrder_Discard(b *testing.B) {
+	enc := NewEncoder(io.Discard)
+	order := &NewOrder{
+		UserID:  123456,
+		Symbol:  "GOOGL",
+		Price:   2750000,
+		Qty:     1000,
+		Side:    SideSell,
+		OrderID: 987654321,
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = enc.EncodeNewOrder(order)
+	}
+}
+
 func BenchmarkEncodeCancel(b *testing.B) {
 	var buf bytes.Buffer
 	enc := NewEncoder(&buf)
@@ -71,6 +131,78 @@ func BenchmarkDecodeBookUpdate(b *testing.B) {
 	}
 }
 
+func BenchmarkBinaryEncodeNewOrder(b *testing.B) {
+	var buf bytes.Buffer
+	enc := NewBinaryEncoder(&buf)
+	order := &NewOrder{
+		UserID:  1,
+		Symbol:  "IBM",
+		Price:   100,
+		Qty:     50,
+		Side:    SideBuy,
+		OrderID: 1,
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		_ = enc.EncodeNewOrder(order)
+	}
+}
+
+func BenchmarkBinaryEncodeCancel(b *testing.B) {
+	var buf bytes.Buffer
+	enc := NewBinaryEncoder(&buf)
+	cancel := &CancelOrder{
+		UserID:  1,
+		OrderID: 1001,
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		_ = enc.EncodeCancel(cancel)
+	}
+}
+
+func BenchmarkBinaryDecodeAck(b *testing.B) {
+	input := binaryAck(binaryTypeAck, "IBM", 1, 1001)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dec := NewBinaryDecoder(bytes.NewReader(input))
+		_, _ = dec.Decode()
+	}
+}
+
+func BenchmarkBinaryDecodeTrade(b *testing.B) {
+	input := binaryTrade(Trade{
+		Symbol:      "IBM",
+		BuyUserID:   1,
+		BuyOrderID:  1001,
+		SellUserID:  2,
+		SellOrderID: 2001,
+		Price:       100,
+		Qty:         50,
+	})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dec := NewBinaryDecoder(bytes.NewReader(input))
+		_, _ = dec.Decode()
+	}
+}
+
+func BenchmarkBinaryDecodeBookUpdate(b *testing.B) {
+	input := binaryBookUpdate(BookUpdate{Symbol: "IBM", Side: SideBuy, Price: 100, Qty: 50})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dec := NewBinaryDecoder(bytes.NewReader(input))
+		_, _ = dec.Decode()
+	}
+}
+
 func BenchmarkValidateOrder(b *testing.B) {
 	order := &NewOrder{
 		UserID:  1,
@@ -86,3 +218,60 @@ func BenchmarkValidateOrder(b *testing.B) {
 		_ = ValidateOrder(order)
 	}
 }
+
+// loopReader replays the same bytes forever so a single decoder can be
+// reused across benchmark iterations.
+type loopReader struct {
+	data []byte
+	off  int
+}
+
+func (r *loopReader) Read(p []byte) (int, error) {
+	n := copy(p, r.data[r.off:])
+	r.off = (r.off + n) % len(r.data)
+	return n, nil
+}
+
+func benchmarkDecodeInto(b *testing.B, input []byte, dec func(r *loopReader) ReusingDecoder) {
+	d := dec(&loopReader{data: input})
+	var msg Message
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := d.DecodeInto(&msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func newCSVDecoder(r *loopReader) ReusingDecoder    { return NewDecoder(r) }
+func newBinaryDecoder(r *loopReader) ReusingDecoder { return NewBinaryDecoder(r) }
+
+func BenchmarkDecodeIntoAck(b *testing.B) {
+	benchmarkDecodeInto(b, frameMessage("A, IBM, 1, 1001"), newCSVDecoder)
+}
+
+func BenchmarkDecodeIntoTrade(b *testing.B) {
+	benchmarkDecodeInto(b, frameMessage("T, IBM, 1, 1001, 2, 2001, 100, 50"), newCSVDecoder)
+}
+
+func BenchmarkDecodeIntoBookUpdate(b *testing.B) {
+	benchmarkDecodeInto(b, frameMessage("B, IBM, B, 100, 50"), newCSVDecoder)
+}
+
+func BenchmarkDecodeIntoCancelAck(b *testing.B) {
+	benchmarkDecodeInto(b, frameMessage("C, IBM, 1, 1001"), newCSVDecoder)
+}
+
+func BenchmarkBinaryDecodeIntoTrade(b *testing.B) {
+	benchmarkDecodeInto(b, binaryTrade(Trade{
+		Symbol:      "IBM",
+		BuyUserID:   1,
+		BuyOrderID:  1001,
+		SellUserID:  2,
+		SellOrderID: 2001,
+		Price:       100,
+		Qty:         50,
+	}), newBinaryDecoder)
+}
@@ -0,0 +1,267 @@
+// Full path: pkg/meclient/protocol/binary.go
+
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Binary wire format constants.
+//
+// Every binary message starts with the magic byte followed by a one-byte
+// message type. All integers are big-endian (network byte order) and
+// symbols are NUL-padded to BinarySymbolSize bytes. Binary messages use
+// the same 4-byte length-prefix framing as CSV.
+const (
+	BinaryMagic      = 0x4D // 'M'
+	BinarySymbolSize = MaxSymbolLength
+)
+
+// Binary message type bytes
+const (
+	binaryTypeNewOrder   = 'N'
+	binaryTypeCancel     = 'C'
+	binaryTypeFlush      = 'F'
+	binaryTypeAck        = 'A'
+	binaryTypeTrade      = 'T'
+	binaryTypeBookUpdate = 'B'
+	binaryTypeCancelAck  = 'X'
+	binaryTypeReject     = 'R'
+	binaryTypeError      = 'E'
+)
+
+// Binary message sizes (payload only, excluding the length prefix)
+const (
+	binaryHeaderSize = 2 // magic + type
+
+	// Outbound
+	BinaryNewOrderSize = binaryHeaderSize + 4 + BinarySymbolSize + 4 + 4 + 1 + 4
+	BinaryCancelSize   = binaryHeaderSize + 4 + 4
+	BinaryFlushSize    = binaryHeaderSize
+
+	// Inbound
+	BinaryAckSize        = binaryHeaderSize + BinarySymbolSize + 4 + 4
+	BinaryTradeSize      = binaryHeaderSize + BinarySymbolSize + 6*4
+	BinaryBookUpdateSize = binaryHeaderSize + BinarySymbolSize + 1 + 4 + 4
+	BinaryCancelAckSize  = binaryHeaderSize + BinarySymbolSize + 4 + 4
+
+	// Minimum sizes; the reason text fills the rest of the payload
+	BinaryRejectSize = binaryHeaderSize + BinarySymbolSize + 4 + 4
+	BinaryErrorSize  = binaryHeaderSize
+
+	maxBinaryOutboundSize = BinaryNewOrderSize
+)
+
+// BinaryEncoder encodes messages to the fixed-layout binary wire format.
+type BinaryEncoder struct {
+	w   io.Writer
+	buf [4 + maxBinaryOutboundSize]byte
+}
+
+// NewBinaryEncoder creates a new binary encoder writing to w.
+func NewBinaryEncoder(w io.Writer) *BinaryEncoder {
+	return &BinaryEncoder{
+		w: w,
+	}
+}
+
+// writeFrame writes the length prefix and the first size bytes of the
+// payload area in a single call.
+func (e *BinaryEncoder) writeFrame(size int) error {
+	binary.BigEndian.PutUint32(e.buf[:4], uint32(size))
+	if _, err := e.w.Write(e.buf[:4+size]); err != nil {
+		return fmt.Errorf("write frame: %w", err)
+	}
+	return nil
+}
+
+// EncodeNewOrder encodes a new order message.
+// Layout: magic, 'N', user_id, symbol[16], price, qty, side, order_id
+func (e *BinaryEncoder) EncodeNewOrder(order *NewOrder) error {
+	if len(order.Symbol) > BinarySymbolSize {
+		return ErrSymbolTooLong
+	}
+
+	p := e.buf[4:]
+	p[0] = BinaryMagic
+	p[1] = binaryTypeNewOrder
+	binary.BigEndian.PutUint32(p[2:], order.UserID)
+	putSymbol(p[6:6+BinarySymbolSize], order.Symbol)
+	off := 6 + BinarySymbolSize
+	binary.BigEndian.PutUint32(p[off:], order.Price)
+	binary.BigEndian.PutUint32(p[off+4:], order.Qty)
+	p[off+8] = byte(SideBuy)
+	if order.Side == SideSell {
+		p[off+8] = byte(SideSell)
+	}
+	binary.BigEndian.PutUint32(p[off+9:], order.OrderID)
+
+	return e.writeFrame(BinaryNewOrderSize)
+}
+
+// EncodeCancel encodes a cancel order message.
+// Layout: magic, 'C', user_id, order_id
+func (e *BinaryEncoder) EncodeCancel(cancel *CancelOrder) error {
+	p := e.buf[4:]
+	p[0] = BinaryMagic
+	p[1] = binaryTypeCancel
+	binary.BigEndian.PutUint32(p[2:], cancel.UserID)
+	binary.BigEndian.PutUint32(p[6:], cancel.OrderID)
+
+	return e.writeFrame(BinaryCancelSize)
+}
+
+// EncodeFlush encodes a flush command.
+// Layout: magic, 'F'
+func (e *BinaryEncoder) EncodeFlush() error {
+	p := e.buf[4:]
+	p[0] = BinaryMagic
+	p[1] = binaryTypeFlush
+
+	return e.writeFrame(BinaryFlushSize)
+}
+
+// putSymbol copies s into dst, NUL-padding the remainder.
+func putSymbol(dst []byte, s string) {
+	n := copy(dst, s)
+	for i := n; i < len(dst); i++ {
+		dst[i] = 0
+	}
+}
+
+// BinaryDecoder decodes messages from the binary wire format.
+type BinaryDecoder struct {
+	r       io.Reader
+	lenBuf  [4]byte
+	buf     []byte
+	symbols symbolTable
+}
+
+// NewBinaryDecoder creates a new binary decoder reading from r.
+func NewBinaryDecoder(r io.Reader) *BinaryDecoder {
+	return &BinaryDecoder{
+		r:   r,
+		buf: make([]byte, MaxFrameSize),
+	}
+}
+
+// Decode reads and decodes the next message.
+func (d *BinaryDecoder) Decode() (*Message, error) {
+	msg := &Message{}
+	if err := d.DecodeInto(msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// DecodeInto reads and decodes the next message into msg.
+func (d *BinaryDecoder) DecodeInto(msg *Message) error {
+	payload, err := readFrame(d.r, &d.lenBuf, d.buf)
+	if err != nil {
+		return err
+	}
+	return parseBinary(payload, msg, &d.symbols)
+}
+
+func parseBinary(p []byte, msg *Message, symbols *symbolTable) error {
+	if len(p) < binaryHeaderSize {
+		return fmt.Errorf("binary: message too short: %d bytes", len(p))
+	}
+	if p[0] != BinaryMagic {
+		return fmt.Errorf("binary: invalid magic byte 0x%02x", p[0])
+	}
+
+	switch p[1] {
+	case binaryTypeAck:
+		if err := checkBinarySize("ack", p, BinaryAckSize); err != nil {
+			return err
+		}
+		symbol, fields := p[2:], p[2+BinarySymbolSize:]
+		ack := msg.setAck()
+		ack.Symbol = symbols.intern(symbolBytes(symbol))
+		ack.UserID = binary.BigEndian.Uint32(fields[0:])
+		ack.OrderID = binary.BigEndian.Uint32(fields[4:])
+		return nil
+
+	case binaryTypeTrade:
+		if err := checkBinarySize("trade", p, BinaryTradeSize); err != nil {
+			return err
+		}
+		symbol, fields := p[2:], p[2+BinarySymbolSize:]
+		trade := msg.setTrade()
+		trade.Symbol = symbols.intern(symbolBytes(symbol))
+		trade.BuyUserID = binary.BigEndian.Uint32(fields[0:])
+		trade.BuyOrderID = binary.BigEndian.Uint32(fields[4:])
+		trade.SellUserID = binary.BigEndian.Uint32(fields[8:])
+		trade.SellOrderID = binary.BigEndian.Uint32(fields[12:])
+		trade.Price = binary.BigEndian.Uint32(fields[16:])
+		trade.Qty = binary.BigEndian.Uint32(fields[20:])
+		return nil
+
+	case binaryTypeBookUpdate:
+		if err := checkBinarySize("book", p, BinaryBookUpdateSize); err != nil {
+			return err
+		}
+		symbol, fields := p[2:], p[2+BinarySymbolSize:]
+		update := msg.setBookUpdate()
+		update.Symbol = symbols.intern(symbolBytes(symbol))
+		update.Side = SideBuy
+		if fields[0] == 'S' || fields[0] == 's' {
+			update.Side = SideSell
+		}
+		update.Price = binary.BigEndian.Uint32(fields[1:])
+		update.Qty = binary.BigEndian.Uint32(fields[5:])
+		return nil
+
+	case binaryTypeCancelAck:
+		if err := checkBinarySize("cancel_ack", p, BinaryCancelAckSize); err != nil {
+			return err
+		}
+		symbol, fields := p[2:], p[2+BinarySymbolSize:]
+		cancelAck := msg.setCancelAck()
+		cancelAck.Symbol = symbols.intern(symbolBytes(symbol))
+		cancelAck.UserID = binary.BigEndian.Uint32(fields[0:])
+		cancelAck.OrderID = binary.BigEndian.Uint32(fields[4:])
+		return nil
+
+	case binaryTypeReject:
+		if err := checkBinarySize("reject", p, BinaryRejectSize); err != nil {
+			return err
+		}
+		symbol, fields := p[2:], p[2+BinarySymbolSize:]
+		reject := msg.setReject()
+		reject.Symbol = symbols.intern(symbolBytes(symbol))
+		reject.UserID = binary.BigEndian.Uint32(fields[0:])
+		reject.OrderID = binary.BigEndian.Uint32(fields[4:])
+		reject.Reason = string(p[BinaryRejectSize:])
+		return nil
+
+	case binaryTypeError:
+		reject := msg.setReject()
+		reject.Reason = string(p[BinaryErrorSize:])
+		return nil
+
+	default:
+		return newUnknownMessage(FormatBinary, p[1:2], p)
+	}
+}
+
+func checkBinarySize(name string, p []byte, want int) error {
+	if len(p) < want {
+		return fmt.Errorf("binary %s: expected %d bytes, got %d", name, want, len(p))
+	}
+	return nil
+}
+
+// symbolBytes returns the symbol stored in a NUL-padded field.
+func symbolBytes(b []byte) []byte {
+	b = b[:BinarySymbolSize]
+	for i, c := range b {
+		if c == 0 {
+			return b[:i]
+		}
+	}
+	return b
+}
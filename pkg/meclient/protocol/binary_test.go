@@ -0,0 +1,309 @@
+// Full path: pkg/meclient/protocol/binary_test.go
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+// Helpers to build framed inbound binary messages as the server would send them.
+func binaryFrame(payload []byte) []byte {
+	buf := make([]byte, 4+len(payload))
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(payload)))
+	copy(buf[4:], payload)
+	return buf
+}
+
+func binaryAck(msgType byte, symbol string, userID, orderID uint32) []byte {
+	p := make([]byte, BinaryAckSize)
+	p[0] = BinaryMagic
+	p[1] = msgType
+	putSymbol(p[2:2+BinarySymbolSize], symbol)
+	binary.BigEndian.PutUint32(p[2+BinarySymbolSize:], userID)
+	binary.BigEndian.PutUint32(p[6+BinarySymbolSize:], orderID)
+	return binaryFrame(p)
+}
+
+func binaryTrade(t Trade) []byte {
+	p := make([]byte, BinaryTradeSize)
+	p[0] = BinaryMagic
+	p[1] = binaryTypeTrade
+	putSymbol(p[2:2+BinarySymbolSize], t.Symbol)
+	f := p[2+BinarySymbolSize:]
+	binary.BigEndian.PutUint32(f[0:], t.BuyUserID)
+	binary.BigEndian.PutUint32(f[4:], t.BuyOrderID)
+	binary.BigEndian.PutUint32(f[8:], t.SellUserID)
+	binary.BigEndian.PutUint32(f[12:], t.SellOrderID)
+	binary.BigEndian.PutUint32(f[16:], t.Price)
+	binary.BigEndian.PutUint32(f[20:], t.Qty)
+	return binaryFrame(p)
+}
+
+func binaryBookUpdate(u BookUpdate) []byte {
+	p := make([]byte, BinaryBookUpdateSize)
+	p[0] = BinaryMagic
+	p[1] = binaryTypeBookUpdate
+	putSymbol(p[2:2+BinarySymbolSize], u.Symbol)
+	f := p[2+BinarySymbolSize:]
+	f[0] = byte(u.Side)
+	binary.BigEndian.PutUint32(f[1:], u.Price)
+	binary.BigEndian.PutUint32(f[5:], u.Qty)
+	return binaryFrame(p)
+}
+
+func binaryReject(r Reject) []byte {
+	p := make([]byte, BinaryRejectSize, BinaryRejectSize+len(r.Reason))
+	p[0] = BinaryMagic
+	p[1] = binaryTypeReject
+	putSymbol(p[2:2+BinarySymbolSize], r.Symbol)
+	binary.BigEndian.PutUint32(p[2+BinarySymbolSize:], r.UserID)
+	binary.BigEndian.PutUint32(p[6+BinarySymbolSize:], r.OrderID)
+	return binaryFrame(append(p, r.Reason...))
+}
+
+func TestBinaryEncodeNewOrder(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewBinaryEncoder(&buf)
+
+	order := &NewOrder{
+		UserID:  7,
+		Symbol:  "AAPL",
+		Price:   15025,
+		Qty:     300,
+		Side:    SideSell,
+		OrderID: 4242,
+	}
+
+	if err := enc.EncodeNewOrder(order); err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+
+	data := buf.Bytes()
+	if len(data) != 4+BinaryNewOrderSize {
+		t.Fatalf("expected %d bytes, got %d", 4+BinaryNewOrderSize, len(data))
+	}
+	if length := binary.BigEndian.Uint32(data[:4]); length != BinaryNewOrderSize {
+		t.Fatalf("expected length prefix %d, got %d", BinaryNewOrderSize, length)
+	}
+
+	p := data[4:]
+	if p[0] != BinaryMagic || p[1] != 'N' {
+		t.Fatalf("unexpected header: % x", p[:2])
+	}
+	if got := binary.BigEndian.Uint32(p[2:]); got != 7 {
+		t.Errorf("user_id: got %d, want 7", got)
+	}
+	if got := string(symbolBytes(p[6:])); got != "AAPL" {
+		t.Errorf("symbol: got %q, want AAPL", got)
+	}
+	off := 6 + BinarySymbolSize
+	if got := binary.BigEndian.Uint32(p[off:]); got != 15025 {
+		t.Errorf("price: got %d, want 15025", got)
+	}
+	if got := binary.BigEndian.Uint32(p[off+4:]); got != 300 {
+		t.Errorf("qty: got %d, want 300", got)
+	}
+	if p[off+8] != 'S' {
+		t.Errorf("side: got %c, want S", p[off+8])
+	}
+	if got := binary.BigEndian.Uint32(p[off+9:]); got != 4242 {
+		t.Errorf("order_id: got %d, want 4242", got)
+	}
+}
+
+func TestBinaryEncodeNewOrder_SymbolTooLong(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewBinaryEncoder(&buf)
+
+	order := &NewOrder{Symbol: "ABCDEFGHIJKLMNOPQ", Qty: 1, Side: SideBuy}
+	if err := enc.EncodeNewOrder(order); err != ErrSymbolTooLong {
+		t.Errorf("expected ErrSymbolTooLong, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing written, got %d bytes", buf.Len())
+	}
+}
+
+func TestBinaryEncodeCancelAndFlush(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewBinaryEncoder(&buf)
+
+	if err := enc.EncodeCancel(&CancelOrder{UserID: 3, OrderID: 99}); err != nil {
+		t.Fatalf("encode cancel error: %v", err)
+	}
+	if err := enc.EncodeFlush(); err != nil {
+		t.Fatalf("encode flush error: %v", err)
+	}
+
+	data := buf.Bytes()
+	if len(data) != 4+BinaryCancelSize+4+BinaryFlushSize {
+		t.Fatalf("unexpected total length %d", len(data))
+	}
+
+	cancel := data[4 : 4+BinaryCancelSize]
+	if cancel[0] != BinaryMagic || cancel[1] != 'C' {
+		t.Errorf("unexpected cancel header: % x", cancel[:2])
+	}
+	if got := binary.BigEndian.Uint32(cancel[2:]); got != 3 {
+		t.Errorf("user_id: got %d, want 3", got)
+	}
+	if got := binary.BigEndian.Uint32(cancel[6:]); got != 99 {
+		t.Errorf("order_id: got %d, want 99", got)
+	}
+
+	flush := data[4+BinaryCancelSize+4:]
+	if flush[0] != BinaryMagic || flush[1] != 'F' {
+		t.Errorf("unexpected flush header: % x", flush)
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	trade := Trade{
+		Symbol:      "IBM",
+		BuyUserID:   1,
+		BuyOrderID:  1001,
+		SellUserID:  2,
+		SellOrderID: 2001,
+		Price:       100,
+		Qty:         50,
+	}
+	update := BookUpdate{Symbol: "MSFT", Side: SideSell, Price: 250, Qty: 10}
+
+	var buf bytes.Buffer
+	buf.Write(binaryAck(binaryTypeAck, "IBM", 1, 1001))
+	buf.Write(binaryTrade(trade))
+	buf.Write(binaryBookUpdate(update))
+	buf.Write(binaryAck(binaryTypeCancelAck, "GOOGL", 4, 4004))
+
+	dec := NewBinaryDecoder(&buf)
+
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("decode ack error: %v", err)
+	}
+	if msg.Ack == nil || *msg.Ack != (Ack{Symbol: "IBM", UserID: 1, OrderID: 1001}) {
+		t.Errorf("unexpected ack: %+v", msg.Ack)
+	}
+
+	msg, err = dec.Decode()
+	if err != nil {
+		t.Fatalf("decode trade error: %v", err)
+	}
+	if msg.Trade == nil || *msg.Trade != trade {
+		t.Errorf("unexpected trade: %+v", msg.Trade)
+	}
+
+	msg, err = dec.Decode()
+	if err != nil {
+		t.Fatalf("decode book error: %v", err)
+	}
+	if msg.BookUpdate == nil || *msg.BookUpdate != update {
+		t.Errorf("unexpected book update: %+v", msg.BookUpdate)
+	}
+
+	msg, err = dec.Decode()
+	if err != nil {
+		t.Fatalf("decode cancel ack error: %v", err)
+	}
+	if msg.CancelAck == nil || *msg.CancelAck != (CancelAck{Symbol: "GOOGL", UserID: 4, OrderID: 4004}) {
+		t.Errorf("unexpected cancel ack: %+v", msg.CancelAck)
+	}
+
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestBinaryDecodeReject(t *testing.T) {
+	reject := Reject{Symbol: "IBM", UserID: 1, OrderID: 1001, Reason: "insufficient liquidity"}
+
+	var buf bytes.Buffer
+	buf.Write(binaryReject(reject))
+	buf.Write(binaryFrame(append([]byte{BinaryMagic, binaryTypeError}, "book halted"...)))
+
+	dec := NewBinaryDecoder(&buf)
+
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("decode reject error: %v", err)
+	}
+	if msg.Reject == nil || *msg.Reject != reject {
+		t.Errorf("unexpected reject: %+v", msg.Reject)
+	}
+
+	msg, err = dec.Decode()
+	if err != nil {
+		t.Fatalf("decode error message error: %v", err)
+	}
+	if msg.Reject == nil || *msg.Reject != (Reject{Reason: "book halted"}) {
+		t.Errorf("unexpected reject: %+v", msg.Reject)
+	}
+}
+
+func TestBinaryDecodeFullWidthSymbol(t *testing.T) {
+	symbol := "ABCDEFGHIJKLMNOP"
+	dec := NewBinaryDecoder(bytes.NewReader(binaryAck(binaryTypeAck, symbol, 1, 2)))
+
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if msg.Ack.Symbol != symbol {
+		t.Errorf("expected symbol %q, got %q", symbol, msg.Ack.Symbol)
+	}
+}
+
+func TestBinaryDecodeUnknownType(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(binaryFrame([]byte{BinaryMagic, 'Z', 1, 2, 3}))
+	buf.Write(binaryAck(binaryTypeAck, "IBM", 1, 1001))
+	dec := NewBinaryDecoder(&buf)
+
+	_, err := dec.Decode()
+	var unknown *UnknownMessageError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected *UnknownMessageError, got %v", err)
+	}
+	if unknown.Raw.Format != FormatBinary || unknown.Raw.Type != "Z" {
+		t.Errorf("unexpected raw message: %+v", unknown.Raw)
+	}
+	if !bytes.Equal(unknown.Raw.Payload, []byte{BinaryMagic, 'Z', 1, 2, 3}) {
+		t.Errorf("unexpected payload % x", unknown.Raw.Payload)
+	}
+	if err.Error() != "binary: unknown message type: 0x5a" {
+		t.Errorf("unexpected error text %q", err.Error())
+	}
+
+	msg, err := dec.Decode()
+	if err != nil || msg.Ack == nil {
+		t.Errorf("expected ack after unknown frame, got %+v (err %v)", msg, err)
+	}
+}
+
+func TestBinaryDecodeErrors(t *testing.T) {
+	ack := binaryAck(binaryTypeAck, "IBM", 1, 1001)
+
+	tests := []struct {
+		name    string
+		payload []byte
+	}{
+		{"too short", []byte{BinaryMagic}},
+		{"bad magic", append([]byte{'A'}, ack[5:]...)},
+		{"unknown type", []byte{BinaryMagic, 'Z', 0, 0}},
+		{"truncated ack", ack[4 : len(ack)-1]},
+		{"truncated reject", []byte{BinaryMagic, binaryTypeReject, 'I', 'B', 'M'}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := NewBinaryDecoder(bytes.NewReader(binaryFrame(tt.payload)))
+			if _, err := dec.Decode(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Full path: pkg/meclient/protocol/codec.go
+
+package protocol
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Built-in codec names
+const (
+	CodecCSV    = "csv"
+	CodecBinary = "binary"
+)
+
+// Codec registry errors
+var (
+	ErrCodecNameEmpty = errors.New("codec name cannot be empty")
+	ErrCodecExists    = errors.New("codec already registered")
+)
+
+// MessageEncoder encodes outbound requests to the wire.
+type MessageEncoder interface {
+	EncodeNewOrder(order *NewOrder) error
+	EncodeCancel(cancel *CancelOrder) error
+	EncodeFlush() error
+}
+
+// MessageDecoder decodes inbound messages from the wire.
+type MessageDecoder interface {
+	// Decode reads the next message. It returns io.EOF when the
+	// stream ends cleanly between messages.
+	Decode() (*Message, error)
+}
+
+// Codec creates encoders and decoders for one wire encoding.
+// Implementations must be safe to share between connections; the
+// encoders and decoders they create are used by a single goroutine.
+type Codec interface {
+	// Name returns the registry name, e.g. "csv".
+	Name() string
+
+	// NewEncoder returns an encoder writing to w.
+	NewEncoder(w io.Writer) MessageEncoder
+
+	// NewDecoder returns a decoder reading from r.
+	NewDecoder(r io.Reader) MessageDecoder
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = make(map[string]Codec)
+)
+
+func init() {
+	_ = RegisterCodec(csvCodec{})
+	_ = RegisterCodec(binaryCodec{})
+}
+
+// RegisterCodec makes a codec available by name to Config.Codec.
+func RegisterCodec(c Codec) error {
+	name := c.Name()
+	if name == "" {
+		return ErrCodecNameEmpty
+	}
+
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	if _, exists := codecs[name]; exists {
+		return ErrCodecExists
+	}
+	codecs[name] = c
+	return nil
+}
+
+// LookupCodec returns the codec registered under name.
+func LookupCodec(name string) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	c, ok := codecs[name]
+	return c, ok
+}
+
+// Codecs returns the sorted names of all registered codecs.
+func Codecs() []string {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// csvCodec is the built-in CSV text encoding.
+type csvCodec struct{}
+
+func (csvCodec) Name() string                          { return CodecCSV }
+func (csvCodec) NewEncoder(w io.Writer) MessageEncoder { return NewEncoder(w) }
+func (csvCodec) NewDecoder(r io.Reader) MessageDecoder { return NewDecoder(r) }
+
+// binaryCodec is the built-in fixed-layout binary encoding.
+type binaryCodec struct{}
+
+func (binaryCodec) Name() string                          { return CodecBinary }
+func (binaryCodec) NewEncoder(w io.Writer) MessageEncoder { return NewBinaryEncoder(w) }
+func (binaryCodec) NewDecoder(r io.Reader) MessageDecoder { return NewBinaryDecoder(r) }
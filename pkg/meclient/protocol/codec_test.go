@@ -0,0 +1,92 @@
+// Full path: pkg/meclient/protocol/codec_test.go
+
+package protocol
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+type testCodec struct{ name string }
+
+func (c testCodec) Name() string                          { return c.name }
+func (c testCodec) NewEncoder(w io.Writer) MessageEncoder { return NewEncoder(w) }
+func (c testCodec) NewDecoder(r io.Reader) MessageDecoder { return NewDecoder(r) }
+
+// unregisterCodec removes a codec registered by a test so the test can
+// run again in the same process.
+func unregisterCodec(t *testing.T, name string) {
+	t.Cleanup(func() {
+		codecsMu.Lock()
+		defer codecsMu.Unlock()
+		delete(codecs, name)
+	})
+}
+
+func TestBuiltinCodecsRegistered(t *testing.T) {
+	for _, name := range []string{CodecCSV, CodecBinary} {
+		codec, ok := LookupCodec(name)
+		if !ok {
+			t.Errorf("codec %q not registered", name)
+			continue
+		}
+		if codec.Name() != name {
+			t.Errorf("expected name %q, got %q", name, codec.Name())
+		}
+	}
+}
+
+func TestLookupCodec_Unknown(t *testing.T) {
+	if _, ok := LookupCodec("does-not-exist"); ok {
+		t.Error("expected lookup of unknown codec to fail")
+	}
+}
+
+func TestRegisterCodec(t *testing.T) {
+	unregisterCodec(t, "test-register")
+	if err := RegisterCodec(testCodec{name: "test-register"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := LookupCodec("test-register"); !ok {
+		t.Error("registered codec not found")
+	}
+
+	if err := RegisterCodec(testCodec{name: "test-register"}); err != ErrCodecExists {
+		t.Errorf("expected ErrCodecExists, got %v", err)
+	}
+	if err := RegisterCodec(testCodec{}); err != ErrCodecNameEmpty {
+		t.Errorf("expected ErrCodecNameEmpty, got %v", err)
+	}
+}
+
+func TestCodecsSorted(t *testing.T) {
+	names := Codecs()
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Errorf("codec names not sorted: %v", names)
+		}
+	}
+}
+
+func TestBinaryCodecRoundTrip(t *testing.T) {
+	codec, _ := LookupCodec(CodecBinary)
+
+	var buf bytes.Buffer
+	enc := codec.NewEncoder(&buf)
+	if err := enc.EncodeFlush(); err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	if got := buf.Bytes(); len(got) != 4+BinaryFlushSize || got[4] != BinaryMagic {
+		t.Errorf("unexpected frame: % x", got)
+	}
+
+	dec := codec.NewDecoder(bytes.NewReader(binaryAck(binaryTypeAck, "IBM", 1, 1001)))
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if msg.Ack == nil || msg.Ack.OrderID != 1001 {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+}
@@ -4,17 +4,47 @@ package protocol
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
-	"strconv"
-	"strings"
 )
 
+// maxCSVFields is the largest field count of any inbound CSV message (trade).
+const maxCSVFields = 8
+
+var errInvalidNumber = errors.New("invalid number")
+
+// ReusingDecoder is implemented by decoders that can decode into a
+// caller-provided Message without allocating.
+type ReusingDecoder interface {
+	MessageDecoder
+
+	// DecodeInto reads the next message into msg. The pointers set on
+	// msg refer to storage inside msg and are overwritten by the next call.
+	DecodeInto(msg *Message) error
+}
+
+// DecodeInto decodes the next message from dec into msg, using the
+// allocation-free path when dec supports it.
+func DecodeInto(dec MessageDecoder, msg *Message) error {
+	if rd, ok := dec.(ReusingDecoder); ok {
+		return rd.DecodeInto(msg)
+	}
+
+	m, err := dec.Decode()
+	if err != nil {
+		return err
+	}
+	*msg = *m
+	return nil
+}
+
 // Decoder decodes messages from the wire format with length-prefix framing.
 type Decoder struct {
-	r      io.Reader
-	lenBuf [4]byte
-	buf    []byte
+	r       io.Reader
+	lenBuf  [4]byte
+	buf     []byte
+	symbols symbolTable
 }
 
 // NewDecoder creates a new decoder reading from r.
@@ -27,12 +57,31 @@ func NewDecoder(r io.Reader) *Decoder {
 
 // Decode reads and decodes the next message.
 func (d *Decoder) Decode() (*Message, error) {
+	msg := &Message{}
+	if err := d.DecodeInto(msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// DecodeInto reads and decodes the next message into msg.
+// It does not allocate once every symbol seen has been interned.
+func (d *Decoder) DecodeInto(msg *Message) error {
+	payload, err := readFrame(d.r, &d.lenBuf, d.buf)
+	if err != nil {
+		return err
+	}
+	return d.parseCSV(payload, msg)
+}
+
+// readFrame reads one length-prefixed frame into buf and returns the payload.
+func readFrame(r io.Reader, lenBuf *[4]byte, buf []byte) ([]byte, error) {
 	// Read 4-byte length header
-	if _, err := io.ReadFull(d.r, d.lenBuf[:]); err != nil {
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
 		return nil, err
 	}
 
-	length := binary.BigEndian.Uint32(d.lenBuf[:])
+	length := binary.BigEndian.Uint32(lenBuf[:])
 
 	// Validate length
 	if length == 0 {
@@ -43,121 +92,125 @@ func (d *Decoder) Decode() (*Message, error) {
 	}
 
 	// Read payload
-	if _, err := io.ReadFull(d.r, d.buf[:length]); err != nil {
+	if _, err := io.ReadFull(r, buf[:length]); err != nil {
 		return nil, fmt.Errorf("read payload: %w", err)
 	}
 
-	// Parse CSV
-	line := strings.TrimSpace(string(d.buf[:length]))
-	return d.parseLine(line)
+	return buf[:length], nil
 }
 
-func (d *Decoder) parseLine(line string) (*Message, error) {
+func (d *Decoder) parseCSV(payload []byte, msg *Message) error {
+	line := trimSpace(payload)
 	if len(line) == 0 {
-		return nil, fmt.Errorf("empty message")
+		return fmt.Errorf("empty message")
 	}
 
 	// Split by comma, handling optional spaces
-	parts := strings.Split(line, ",")
-	for i := range parts {
-		parts[i] = strings.TrimSpace(parts[i])
+	var fields [maxCSVFields][]byte
+	n := splitFields(line, &fields)
+	if n > maxCSVFields {
+		n = maxCSVFields
 	}
+	parts := fields[:n]
 
 	msgType := parts[0]
+	if len(msgType) != 1 {
+		return newUnknownMessage(FormatCSV, msgType, payload)
+	}
 
-	switch msgType {
-	case "A":
-		return d.parseAck(parts)
-	case "T":
-		return d.parseTrade(parts)
-	case "B":
-		return d.parseBookUpdate(parts)
-	case "C":
-		return d.parseCancelAck(parts)
+	switch msgType[0] {
+	case 'A':
+		return d.parseAck(parts, msg)
+	case 'T':
+		return d.parseTrade(parts, msg)
+	case 'B':
+		return d.parseBookUpdate(parts, msg)
+	case 'C':
+		return d.parseCancelAck(parts, msg)
+	case 'R':
+		return d.parseReject(line, parts, msg)
+	case 'E':
+		return d.parseServerError(line, msg)
 	default:
-		return nil, fmt.Errorf("unknown message type: %s", msgType)
+		return newUnknownMessage(FormatCSV, msgType, payload)
 	}
 }
 
-func (d *Decoder) parseAck(parts []string) (*Message, error) {
+func (d *Decoder) parseAck(parts [][]byte, msg *Message) error {
 	// A, symbol, user_id, order_id
 	if len(parts) < 4 {
-		return nil, fmt.Errorf("ack: expected 4 fields, got %d", len(parts))
+		return fmt.Errorf("ack: expected 4 fields, got %d", len(parts))
 	}
 
-	userID, err := strconv.ParseUint(parts[2], 10, 32)
+	userID, err := parseUint32(parts[2])
 	if err != nil {
-		return nil, fmt.Errorf("ack: invalid user_id: %w", err)
+		return fmt.Errorf("ack: invalid user_id: %w", err)
 	}
 
-	orderID, err := strconv.ParseUint(parts[3], 10, 32)
+	orderID, err := parseUint32(parts[3])
 	if err != nil {
-		return nil, fmt.Errorf("ack: invalid order_id: %w", err)
+		return fmt.Errorf("ack: invalid order_id: %w", err)
 	}
 
-	return &Message{
-		Ack: &Ack{
-			Symbol:  parts[1],
-			UserID:  uint32(userID),
-			OrderID: uint32(orderID),
-		},
-	}, nil
+	ack := msg.setAck()
+	ack.Symbol = d.symbols.intern(parts[1])
+	ack.UserID = userID
+	ack.OrderID = orderID
+	return nil
 }
 
-func (d *Decoder) parseTrade(parts []string) (*Message, error) {
+func (d *Decoder) parseTrade(parts [][]byte, msg *Message) error {
 	// T, symbol, buy_user, buy_oid, sell_user, sell_oid, price, qty
 	if len(parts) < 8 {
-		return nil, fmt.Errorf("trade: expected 8 fields, got %d", len(parts))
+		return fmt.Errorf("trade: expected 8 fields, got %d", len(parts))
 	}
 
-	buyUserID, err := strconv.ParseUint(parts[2], 10, 32)
+	buyUserID, err := parseUint32(parts[2])
 	if err != nil {
-		return nil, fmt.Errorf("trade: invalid buy_user_id: %w", err)
+		return fmt.Errorf("trade: invalid buy_user_id: %w", err)
 	}
 
-	buyOrderID, err := strconv.ParseUint(parts[3], 10, 32)
+	buyOrderID, err := parseUint32(parts[3])
 	if err != nil {
-		return nil, fmt.Errorf("trade: invalid buy_order_id: %w", err)
+		return fmt.Errorf("trade: invalid buy_order_id: %w", err)
 	}
 
-	sellUserID, err := strconv.ParseUint(parts[4], 10, 32)
+	sellUserID, err := parseUint32(parts[4])
 	if err != nil {
-		return nil, fmt.Errorf("trade: invalid sell_user_id: %w", err)
+		return fmt.Errorf("trade: invalid sell_user_id: %w", err)
 	}
 
-	sellOrderID, err := strconv.ParseUint(parts[5], 10, 32)
+	sellOrderID, err := parseUint32(parts[5])
 	if err != nil {
-		return nil, fmt.Errorf("trade: invalid sell_order_id: %w", err)
+		return fmt.Errorf("trade: invalid sell_order_id: %w", err)
 	}
 
-	price, err := strconv.ParseUint(parts[6], 10, 32)
+	price, err := parseUint32(parts[6])
 	if err != nil {
-		return nil, fmt.Errorf("trade: invalid price: %w", err)
+		return fmt.Errorf("trade: invalid price: %w", err)
 	}
 
-	qty, err := strconv.ParseUint(parts[7], 10, 32)
+	qty, err := parseUint32(parts[7])
 	if err != nil {
-		return nil, fmt.Errorf("trade: invalid qty: %w", err)
+		return fmt.Errorf("trade: invalid qty: %w", err)
 	}
 
-	return &Message{
-		Trade: &Trade{
-			Symbol:      parts[1],
-			BuyUserID:   uint32(buyUserID),
-			BuyOrderID:  uint32(buyOrderID),
-			SellUserID:  uint32(sellUserID),
-			SellOrderID: uint32(sellOrderID),
-			Price:       uint32(price),
-			Qty:         uint32(qty),
-		},
-	}, nil
+	trade := msg.setTrade()
+	trade.Symbol = d.symbols.intern(parts[1])
+	trade.BuyUserID = buyUserID
+	trade.BuyOrderID = buyOrderID
+	trade.SellUserID = sellUserID
+	trade.SellOrderID = sellOrderID
+	trade.Price = price
+	trade.Qty = qty
+	return nil
 }
 
-func (d *Decoder) parseBookUpdate(parts []string) (*Message, error) {
+func (d *Decoder) parseBookUpdate(parts [][]byte, msg *Message) error {
 	// B, symbol, side, price, qty
 	// or B, symbol, side, -, - (empty book)
 	if len(parts) < 5 {
-		return nil, fmt.Errorf("book: expected 5 fields, got %d", len(parts))
+		return fmt.Errorf("book: expected 5 fields, got %d", len(parts))
 	}
 
 	side := SideBuy
@@ -166,54 +219,151 @@ func (d *Decoder) parseBookUpdate(parts []string) (*Message, error) {
 	}
 
 	var price, qty uint32
+	var err error
 
-	if parts[3] != "-" {
-		p, err := strconv.ParseUint(parts[3], 10, 32)
-		if err != nil {
-			return nil, fmt.Errorf("book: invalid price: %w", err)
+	if !isDash(parts[3]) {
+		if price, err = parseUint32(parts[3]); err != nil {
+			return fmt.Errorf("book: invalid price: %w", err)
 		}
-		price = uint32(p)
 	}
 
-	if parts[4] != "-" {
-		q, err := strconv.ParseUint(parts[4], 10, 32)
-		if err != nil {
-			return nil, fmt.Errorf("book: invalid qty: %w", err)
+	if !isDash(parts[4]) {
+		if qty, err = parseUint32(parts[4]); err != nil {
+			return fmt.Errorf("book: invalid qty: %w", err)
 		}
-		qty = uint32(q)
 	}
 
-	return &Message{
-		BookUpdate: &BookUpdate{
-			Symbol: parts[1],
-			Side:   side,
-			Price:  price,
-			Qty:    qty,
-		},
-	}, nil
+	update := msg.setBookUpdate()
+	update.Symbol = d.symbols.intern(parts[1])
+	update.Side = side
+	update.Price = price
+	update.Qty = qty
+	return nil
 }
 
-func (d *Decoder) parseCancelAck(parts []string) (*Message, error) {
+func (d *Decoder) parseCancelAck(parts [][]byte, msg *Message) error {
 	// C, symbol, user_id, order_id
 	if len(parts) < 4 {
-		return nil, fmt.Errorf("cancel_ack: expected 4 fields, got %d", len(parts))
+		return fmt.Errorf("cancel_ack: expected 4 fields, got %d", len(parts))
+	}
+
+	userID, err := parseUint32(parts[2])
+	if err != nil {
+		return fmt.Errorf("cancel_ack: invalid user_id: %w", err)
+	}
+
+	orderID, err := parseUint32(parts[3])
+	if err != nil {
+		return fmt.Errorf("cancel_ack: invalid order_id: %w", err)
+	}
+
+	cancelAck := msg.setCancelAck()
+	cancelAck.Symbol = d.symbols.intern(parts[1])
+	cancelAck.UserID = userID
+	cancelAck.OrderID = orderID
+	return nil
+}
+
+func (d *Decoder) parseReject(line []byte, parts [][]byte, msg *Message) error {
+	// R, symbol, user_id, order_id, reason
+	// The reason is free text and may itself contain commas.
+	if len(parts) < 4 {
+		return fmt.Errorf("reject: expected at least 4 fields, got %d", len(parts))
 	}
 
-	userID, err := strconv.ParseUint(parts[2], 10, 32)
+	userID, err := parseUint32(parts[2])
 	if err != nil {
-		return nil, fmt.Errorf("cancel_ack: invalid user_id: %w", err)
+		return fmt.Errorf("reject: invalid user_id: %w", err)
 	}
 
-	orderID, err := strconv.ParseUint(parts[3], 10, 32)
+	orderID, err := parseUint32(parts[3])
 	if err != nil {
-		return nil, fmt.Errorf("cancel_ack: invalid order_id: %w", err)
+		return fmt.Errorf("reject: invalid order_id: %w", err)
 	}
 
-	return &Message{
-		CancelAck: &CancelAck{
-			Symbol:  parts[1],
-			UserID:  uint32(userID),
-			OrderID: uint32(orderID),
-		},
-	}, nil
+	reject := msg.setReject()
+	reject.Symbol = d.symbols.intern(parts[1])
+	reject.UserID = userID
+	reject.OrderID = orderID
+	reject.Reason = string(afterFields(line, 4))
+	return nil
+}
+
+func (d *Decoder) parseServerError(line []byte, msg *Message) error {
+	// E, reason
+	reject := msg.setReject()
+	reject.Reason = string(afterFields(line, 1))
+	return nil
+}
+
+// afterFields returns the trimmed remainder of line following the first
+// n comma-separated fields, or nil if line has no more than n fields.
+func afterFields(line []byte, n int) []byte {
+	for i, c := range line {
+		if c != ',' {
+			continue
+		}
+		n--
+		if n == 0 {
+			return trimSpace(line[i+1:])
+		}
+	}
+	return nil
+}
+
+// splitFields splits line on commas into fields, trimming each one.
+// It returns the total number of fields, which may exceed len(fields);
+// fields beyond capacity are not stored.
+func splitFields(line []byte, fields *[maxCSVFields][]byte) int {
+	n := 0
+	start := 0
+	for i := 0; i <= len(line); i++ {
+		if i < len(line) && line[i] != ',' {
+			continue
+		}
+		if n < maxCSVFields {
+			fields[n] = trimSpace(line[start:i])
+		}
+		n++
+		start = i + 1
+	}
+	return n
+}
+
+// parseUint32 parses a base-10 unsigned integer without allocating.
+func parseUint32(b []byte) (uint32, error) {
+	if len(b) == 0 {
+		return 0, fmt.Errorf("%w: empty", errInvalidNumber)
+	}
+
+	var v uint64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("%w: %q", errInvalidNumber, b)
+		}
+		v = v*10 + uint64(c-'0')
+		if v > 1<<32-1 {
+			return 0, fmt.Errorf("%w: %q out of range", errInvalidNumber, b)
+		}
+	}
+	return uint32(v), nil
+}
+
+func isDash(b []byte) bool {
+	return len(b) == 1 && b[0] == '-'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+// trimSpace trims leading and trailing ASCII whitespace.
+func trimSpace(b []byte) []byte {
+	for len(b) > 0 && isSpace(b[0]) {
+		b = b[1:]
+	}
+	for len(b) > 0 && isSpace(b[len(b)-1]) {
+		b = b[:len(b)-1]
+	}
+	return b
 }
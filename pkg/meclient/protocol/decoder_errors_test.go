@@ -5,6 +5,7 @@ package protocol
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"io"
 	"testing"
 )
@@ -19,6 +20,43 @@ func TestDecodeUnknownMessageType(t *testing.T) {
 	}
 }
 
+func TestDecodeUnknownMessageType_Raw(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(frameMessage("Q, IBM, 42"))
+	buf.Write(frameMessage("A, IBM, 1, 1001"))
+	dec := NewDecoder(&buf)
+
+	_, err := dec.Decode()
+	if !errors.Is(err, ErrUnknownMessageType) {
+		t.Fatalf("expected ErrUnknownMessageType, got %v", err)
+	}
+
+	var unknown *UnknownMessageError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected *UnknownMessageError, got %T", err)
+	}
+	if unknown.Raw.Format != FormatCSV || unknown.Raw.Type != "Q" {
+		t.Errorf("unexpected raw message: %+v", unknown.Raw)
+	}
+	if string(unknown.Raw.Payload) != "Q, IBM, 42" {
+		t.Errorf("unexpected payload %q", unknown.Raw.Payload)
+	}
+
+	// The unknown frame was consumed; the next one decodes normally
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("decode error after unknown frame: %v", err)
+	}
+	if msg.Ack == nil || msg.Ack.OrderID != 1001 {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+
+	// The payload is a copy and survives later decodes
+	if string(unknown.Raw.Payload) != "Q, IBM, 42" {
+		t.Errorf("payload overwritten: %q", unknown.Raw.Payload)
+	}
+}
+
 func TestDecodeInvalidAckFields(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -69,6 +107,27 @@ func TestDecodeInvalidTradeFields(t *testing.T) {
 	}
 }
 
+func TestDecodeInvalidRejectFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"too few fields", "R, IBM, 1"},
+		{"invalid user_id", "R, IBM, notanumber, 1001, reason"},
+		{"invalid order_id", "R, IBM, 1, notanumber, reason"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := NewDecoder(bytes.NewReader(frameMessage(tt.input)))
+			_, err := dec.Decode()
+			if err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
 func TestDecodeInvalidBookUpdateFields(t *testing.T) {
 	tests := []struct {
 		name  string
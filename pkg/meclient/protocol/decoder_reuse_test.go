@@ -0,0 +1,170 @@
+// Full path: pkg/meclient/protocol/decoder_reuse_test.go
+
+package protocol
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"unsafe"
+)
+
+func TestDecodeInto_ReusesMessage(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(frameMessage("A, IBM, 1, 1001"))
+	buf.Write(frameMessage("T, IBM, 1, 1001, 2, 2001, 100, 50"))
+	buf.Write(frameMessage("B, IBM, S, -, -"))
+
+	dec := NewDecoder(&buf)
+	var msg Message
+
+	if err := dec.DecodeInto(&msg); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if msg.Ack == nil || msg.Ack.OrderID != 1001 {
+		t.Fatalf("expected ack, got %+v", msg)
+	}
+
+	if err := dec.DecodeInto(&msg); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if msg.Ack != nil {
+		t.Error("ack should be cleared after decoding a trade")
+	}
+	if msg.Trade == nil || msg.Trade.Qty != 50 {
+		t.Fatalf("expected trade, got %+v", msg)
+	}
+
+	if err := dec.DecodeInto(&msg); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if msg.Trade != nil {
+		t.Error("trade should be cleared after decoding a book update")
+	}
+	if msg.BookUpdate == nil || msg.BookUpdate.Side != SideSell || msg.BookUpdate.Price != 0 {
+		t.Fatalf("expected empty sell book update, got %+v", msg.BookUpdate)
+	}
+}
+
+func TestDecodeInto_InternsSymbols(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(frameMessage("A, IBM, 1, 1001"))
+	buf.Write(frameMessage("A, IBM, 1, 1002"))
+
+	dec := NewDecoder(&buf)
+	first, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	second, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if unsafe.StringData(first.Ack.Symbol) != unsafe.StringData(second.Ack.Symbol) {
+		t.Error("expected repeated symbol to share storage")
+	}
+}
+
+func TestDecodeInto_ZeroAllocs(t *testing.T) {
+	frames := []string{
+		"A, IBM, 1, 1001",
+		"T, IBM, 1, 1001, 2, 2001, 100, 50",
+		"B, IBM, B, 100, 50",
+		"B, IBM, S, -, -",
+		"C, IBM, 1, 1001",
+	}
+
+	for _, frame := range frames {
+		input := frameMessage(frame)
+		r := bytes.NewReader(input)
+		dec := NewDecoder(r)
+		var msg Message
+
+		// Warm the symbol table
+		if err := dec.DecodeInto(&msg); err != nil {
+			t.Fatalf("decode %q: %v", frame, err)
+		}
+
+		allocs := testing.AllocsPerRun(100, func() {
+			r.Reset(input)
+			if err := dec.DecodeInto(&msg); err != nil {
+				t.Fatalf("decode %q: %v", frame, err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("%q: expected 0 allocs/op, got %v", frame, allocs)
+		}
+	}
+}
+
+func TestBinaryDecodeInto_ZeroAllocs(t *testing.T) {
+	input := binaryAck(binaryTypeAck, "IBM", 1, 1001)
+	r := bytes.NewReader(input)
+	dec := NewBinaryDecoder(r)
+	var msg Message
+
+	if err := dec.DecodeInto(&msg); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		r.Reset(input)
+		_ = dec.DecodeInto(&msg)
+	})
+	if allocs != 0 {
+		t.Errorf("expected 0 allocs/op, got %v", allocs)
+	}
+}
+
+type plainDecoder struct{ msg *Message }
+
+func (d plainDecoder) Decode() (*Message, error) {
+	if d.msg == nil {
+		return nil, io.EOF
+	}
+	return d.msg, nil
+}
+
+func TestDecodeIntoHelper_FallsBackToDecode(t *testing.T) {
+	var msg Message
+	src := &Message{CancelAck: &CancelAck{Symbol: "IBM", UserID: 1, OrderID: 7}}
+
+	if err := DecodeInto(plainDecoder{msg: src}, &msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.CancelAck == nil || msg.CancelAck.OrderID != 7 {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+
+	if err := DecodeInto(plainDecoder{}, &msg); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestParseUint32(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint32
+		wantErr bool
+	}{
+		{"0", 0, false},
+		{"4294967295", 4294967295, false},
+		{"4294967296", 0, true},
+		{"", 0, true},
+		{"-1", 0, true},
+		{"+1", 0, true},
+		{"12a", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseUint32([]byte(tt.in))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: unexpected error state: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: expected %d, got %d", tt.in, tt.want, got)
+		}
+	}
+}
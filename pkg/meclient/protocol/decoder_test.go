@@ -234,3 +234,56 @@ func TestDecodeLargeValues(t *testing.T) {
 		t.Errorf("expected order_id max uint32, got %d", msg.Ack.OrderID)
 	}
 }
+
+func TestDecodeReject(t *testing.T) {
+	input := frameMessage("R, IBM, 1, 1001, price outside collar, limit 105")
+	dec := NewDecoder(bytes.NewReader(input))
+
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if msg.Reject == nil {
+		t.Fatal("expected Reject message")
+	}
+
+	want := Reject{Symbol: "IBM", UserID: 1, OrderID: 1001, Reason: "price outside collar, limit 105"}
+	if *msg.Reject != want {
+		t.Errorf("expected %+v, got %+v", want, *msg.Reject)
+	}
+}
+
+func TestDecodeRejectNoReason(t *testing.T) {
+	input := frameMessage("R, IBM, 1, 1001")
+	dec := NewDecoder(bytes.NewReader(input))
+
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if msg.Reject == nil || msg.Reject.OrderID != 1001 {
+		t.Fatalf("unexpected reject: %+v", msg.Reject)
+	}
+	if msg.Reject.Reason != "" {
+		t.Errorf("expected empty reason, got %q", msg.Reject.Reason)
+	}
+}
+
+func TestDecodeServerError(t *testing.T) {
+	input := frameMessage("E, unknown symbol XYZ")
+	dec := NewDecoder(bytes.NewReader(input))
+
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if msg.Reject == nil {
+		t.Fatal("expected Reject message")
+	}
+	if *msg.Reject != (Reject{Reason: "unknown symbol XYZ"}) {
+		t.Errorf("unexpected reject: %+v", *msg.Reject)
+	}
+}
@@ -0,0 +1,87 @@
+// Full path: pkg/meclient/protocol/detect.go
+
+package protocol
+
+import "io"
+
+// Format identifies a wire encoding.
+type Format uint8
+
+const (
+	FormatUnknown Format = iota // Not yet detected
+	FormatCSV                   // CSV text
+	FormatBinary                // Fixed-layout binary
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatCSV:
+		return "csv"
+	case FormatBinary:
+		return "binary"
+	default:
+		return "unknown"
+	}
+}
+
+// DetectFormat returns the encoding of a frame payload.
+// Binary payloads start with BinaryMagic; CSV message types never do.
+func DetectFormat(payload []byte) Format {
+	if len(payload) == 0 {
+		return FormatUnknown
+	}
+	if payload[0] == BinaryMagic {
+		return FormatBinary
+	}
+	return FormatCSV
+}
+
+// AutoDecoder sniffs the first inbound frame to detect the server's
+// encoding and decodes every later frame with that encoding.
+type AutoDecoder struct {
+	r      io.Reader
+	lenBuf [4]byte
+	buf    []byte
+	csv    Decoder
+	format Format
+}
+
+// NewAutoDecoder creates a new auto-detecting decoder reading from r.
+func NewAutoDecoder(r io.Reader) *AutoDecoder {
+	return &AutoDecoder{
+		r:   r,
+		buf: make([]byte, MaxFrameSize),
+	}
+}
+
+// Decode reads and decodes the next message.
+func (d *AutoDecoder) Decode() (*Message, error) {
+	msg := &Message{}
+	if err := d.DecodeInto(msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// DecodeInto reads and decodes the next message into msg.
+func (d *AutoDecoder) DecodeInto(msg *Message) error {
+	payload, err := readFrame(d.r, &d.lenBuf, d.buf)
+	if err != nil {
+		return err
+	}
+
+	if d.format == FormatUnknown {
+		d.format = DetectFormat(payload)
+	}
+
+	if d.format == FormatBinary {
+		return parseBinary(payload, msg, &d.csv.symbols)
+	}
+	return d.csv.parseCSV(payload, msg)
+}
+
+// Format returns the detected encoding, or FormatUnknown before the
+// first frame has been read.
+func (d *AutoDecoder) Format() Format {
+	return d.format
+}
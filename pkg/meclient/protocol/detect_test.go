@@ -0,0 +1,88 @@
+// Full path: pkg/meclient/protocol/detect_test.go
+
+package protocol
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []byte
+		want    Format
+	}{
+		{"empty", nil, FormatUnknown},
+		{"csv ack", []byte("A, IBM, 1, 1001"), FormatCSV},
+		{"binary", []byte{BinaryMagic, 'A'}, FormatBinary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.payload); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAutoDecoder_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(frameMessage("A, IBM, 1, 1001"))
+	buf.Write(frameMessage("C, IBM, 1, 1001"))
+
+	dec := NewAutoDecoder(&buf)
+	if dec.Format() != FormatUnknown {
+		t.Fatalf("expected unknown format before first frame, got %v", dec.Format())
+	}
+
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if msg.Ack == nil {
+		t.Fatal("expected Ack message")
+	}
+	if dec.Format() != FormatCSV {
+		t.Errorf("expected csv, got %v", dec.Format())
+	}
+
+	msg, err = dec.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if msg.CancelAck == nil {
+		t.Error("expected CancelAck message")
+	}
+}
+
+func TestAutoDecoder_BinaryLocks(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(binaryAck(binaryTypeAck, "IBM", 1, 1001))
+	buf.Write(frameMessage("A, IBM, 1, 1002"))
+
+	dec := NewAutoDecoder(&buf)
+
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if msg.Ack == nil || msg.Ack.OrderID != 1001 {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	if dec.Format() != FormatBinary {
+		t.Fatalf("expected binary, got %v", dec.Format())
+	}
+
+	// Locked to binary: a CSV frame is now a decode error
+	if _, err := dec.Decode(); err == nil {
+		t.Error("expected error decoding CSV after locking to binary")
+	}
+}
+
+func TestFormatString(t *testing.T) {
+	if FormatCSV.String() != "csv" || FormatBinary.String() != "binary" || FormatUnknown.String() != "unknown" {
+		t.Error("unexpected format strings")
+	}
+}
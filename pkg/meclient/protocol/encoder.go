@@ -6,6 +6,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"strconv"
 )
 
 const (
@@ -13,34 +14,39 @@ const (
 	MaxFrameSize = 16384
 )
 
+// csvFrameCapacity covers the largest validated CSV order:
+// 4-byte prefix + "N," + 4 uint32 fields + 16-char symbol + side + separators.
+const csvFrameCapacity = 128
+
 // Encoder encodes messages to the wire format with length-prefix framing.
+// Each frame is built in a reused buffer and written with a single call.
 type Encoder struct {
-	w      io.Writer
-	lenBuf [4]byte
+	w   io.Writer
+	buf []byte // 4-byte length prefix followed by the payload
 }
 
 // NewEncoder creates a new encoder writing to w.
 func NewEncoder(w io.Writer) *Encoder {
 	return &Encoder{
-		w: w,
+		w:   w,
+		buf: make([]byte, 4, csvFrameCapacity),
 	}
 }
 
-// writeFrame writes a length-prefixed frame.
-func (e *Encoder) writeFrame(payload []byte) error {
-	if len(payload) > MaxFrameSize {
-		return fmt.Errorf("message too large: %d > %d", len(payload), MaxFrameSize)
-	}
+// writeFrame fills in the length prefix of frame and writes it.
+func (e *Encoder) writeFrame(frame []byte) error {
+	// Keep any growth for the next message
+	e.buf = frame[:4]
 
-	// Write 4-byte big-endian length
-	binary.BigEndian.PutUint32(e.lenBuf[:], uint32(len(payload)))
-	if _, err := e.w.Write(e.lenBuf[:]); err != nil {
-		return fmt.Errorf("write length: %w", err)
+	length := len(frame) - 4
+	if length > MaxFrameSize {
+		return fmt.Errorf("message too large: %d > %d", length, MaxFrameSize)
 	}
 
-	// Write payload
-	if _, err := e.w.Write(payload); err != nil {
-		return fmt.Errorf("write payload: %w", err)
+	// 4-byte big-endian length
+	binary.BigEndian.PutUint32(frame[:4], uint32(length))
+	if _, err := e.w.Write(frame); err != nil {
+		return fmt.Errorf("write frame: %w", err)
 	}
 
 	return nil
@@ -54,29 +60,35 @@ func (e *Encoder) EncodeNewOrder(order *NewOrder) error {
 		side = 'S'
 	}
 
-	payload := fmt.Sprintf("N,%d,%s,%d,%d,%c,%d\n",
-		order.UserID,
-		order.Symbol,
-		order.Price,
-		order.Qty,
-		side,
-		order.OrderID)
+	b := append(e.buf[:4], 'N', ',')
+	b = strconv.AppendUint(b, uint64(order.UserID), 10)
+	b = append(b, ',')
+	b = append(b, order.Symbol...)
+	b = append(b, ',')
+	b = strconv.AppendUint(b, uint64(order.Price), 10)
+	b = append(b, ',')
+	b = strconv.AppendUint(b, uint64(order.Qty), 10)
+	b = append(b, ',', side, ',')
+	b = strconv.AppendUint(b, uint64(order.OrderID), 10)
+	b = append(b, '\n')
 
-	return e.writeFrame([]byte(payload))
+	return e.writeFrame(b)
 }
 
 // EncodeCancel encodes a cancel order message.
 // Format: C,user_id,order_id\n (no symbol per message_parser.c)
 func (e *Encoder) EncodeCancel(cancel *CancelOrder) error {
-	payload := fmt.Sprintf("C,%d,%d\n",
-		cancel.UserID,
-		cancel.OrderID)
+	b := append(e.buf[:4], 'C', ',')
+	b = strconv.AppendUint(b, uint64(cancel.UserID), 10)
+	b = append(b, ',')
+	b = strconv.AppendUint(b, uint64(cancel.OrderID), 10)
+	b = append(b, '\n')
 
-	return e.writeFrame([]byte(payload))
+	return e.writeFrame(b)
 }
 
 // EncodeFlush encodes a flush command.
 // Format: F\n
 func (e *Encoder) EncodeFlush() error {
-	return e.writeFrame([]byte("F\n"))
+	return e.writeFrame(append(e.buf[:4], 'F', '\n'))
 }
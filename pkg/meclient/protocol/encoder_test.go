@@ -5,6 +5,7 @@ package protocol
 import (
 	"bytes"
 	"encoding/binary"
+	"io"
 	"testing"
 )
 
@@ -192,3 +193,77 @@ func TestEncodeLargeValues(t *testing.T) {
 		t.Error("expected non-empty buffer")
 	}
 }
+
+// countingWriter records the number of Write calls.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestEncoder_SingleWritePerFrame(t *testing.T) {
+	var w countingWriter
+	enc := NewEncoder(&w)
+
+	order := &NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 50, Side: SideBuy, OrderID: 1}
+	if err := enc.EncodeNewOrder(order); err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	if err := enc.EncodeCancel(&CancelOrder{UserID: 1, OrderID: 1}); err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	if err := enc.EncodeFlush(); err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+
+	if w.writes != 3 {
+		t.Errorf("expected 3 writes for 3 frames, got %d", w.writes)
+	}
+}
+
+func TestEncoder_ZeroAllocs(t *testing.T) {
+	enc := NewEncoder(io.Discard)
+	order := &NewOrder{
+		UserID:  4294967295,
+		Symbol:  "ABCDEFGHIJKLMNOP",
+		Price:   4294967295,
+		Qty:     4294967295,
+		Side:    SideSell,
+		OrderID: 4294967295,
+	}
+	cancel := &CancelOrder{UserID: 4294967295, OrderID: 4294967295}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = enc.EncodeNewOrder(order)
+		_ = enc.EncodeCancel(cancel)
+		_ = enc.EncodeFlush()
+	})
+	if allocs != 0 {
+		t.Errorf("expected 0 allocs/op, got %v", allocs)
+	}
+}
+
+func TestEncoder_MessageTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+
+	order := &NewOrder{Symbol: string(make([]byte, MaxFrameSize)), Qty: 1, Side: SideBuy}
+	if err := enc.EncodeNewOrder(order); err == nil {
+		t.Error("expected error for oversized message")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing written, got %d bytes", buf.Len())
+	}
+
+	// Encoder remains usable after an oversized message
+	if err := enc.EncodeFlush(); err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	if got := buf.Bytes()[4:]; string(got) != "F\n" {
+		t.Errorf("expected flush payload, got %q", got)
+	}
+}
@@ -0,0 +1,30 @@
+// Full path: pkg/meclient/protocol/handler.go
+
+package protocol
+
+// Handler receives client events by direct call instead of through
+// channels. Message methods run inline on the client's read goroutine,
+// in wire order, so they must return quickly and must not block; a slow
+// handler delays every later message. OnError may also be called from
+// the write goroutine.
+type Handler interface {
+	OnAck(ack Ack)
+	OnTrade(trade Trade)
+	OnBookUpdate(update BookUpdate)
+	OnCancelAck(cancelAck CancelAck)
+	OnReject(reject Reject)
+	OnError(err error)
+	OnReconnect(event ReconnectEvent)
+}
+
+// NopHandler implements Handler with methods that do nothing. Embed it
+// to implement only the callbacks of interest.
+type NopHandler struct{}
+
+func (NopHandler) OnAck(Ack)                  {}
+func (NopHandler) OnTrade(Trade)              {}
+func (NopHandler) OnBookUpdate(BookUpdate)    {}
+func (NopHandler) OnCancelAck(CancelAck)      {}
+func (NopHandler) OnReject(Reject)            {}
+func (NopHandler) OnError(error)              {}
+func (NopHandler) OnReconnect(ReconnectEvent) {}
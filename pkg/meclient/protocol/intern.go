@@ -0,0 +1,28 @@
+// Full path: pkg/meclient/protocol/intern.go
+
+package protocol
+
+// MaxInternedSymbols bounds the per-decoder symbol table. Symbols beyond
+// the limit are still decoded, but allocate a new string each time.
+const MaxInternedSymbols = 4096
+
+// symbolTable interns symbol strings so repeated symbols cost no allocation.
+type symbolTable struct {
+	m map[string]string
+}
+
+// intern returns a string equal to b, reusing a previously seen copy.
+func (t *symbolTable) intern(b []byte) string {
+	if s, ok := t.m[string(b)]; ok {
+		return s
+	}
+
+	s := string(b)
+	if t.m == nil {
+		t.m = make(map[string]string)
+	}
+	if len(t.m) < MaxInternedSymbols {
+		t.m[s] = s
+	}
+	return s
+}
@@ -72,15 +72,81 @@ type CancelAck struct {
 	OrderID uint32
 }
 
+// Reject reports that the server refused a request. Order rejects carry
+// the order's symbol and IDs; general server errors leave them zero and
+// only set Reason.
+type Reject struct {
+	Symbol  string
+	UserID  uint32
+	OrderID uint32
+	Reason  string
+}
+
 // ReconnectEvent is sent when the client reconnects.
 type ReconnectEvent struct {
-	Attempt int
+	Attempt       int
+	UnknownOrders int // Tracked orders open when the connection dropped
+	CancelsSent   int // Cancels queued for them under DisconnectCancel
 }
 
 // Message is a union type for all possible server responses.
+// Exactly one of the pointer fields is set after a successful decode.
 type Message struct {
 	Ack        *Ack
 	Trade      *Trade
 	BookUpdate *BookUpdate
 	CancelAck  *CancelAck
+	Reject     *Reject
+
+	// Backing storage used by DecodeInto so a reused Message needs no
+	// allocation. The pointer fields above refer into it.
+	ack        Ack
+	trade      Trade
+	bookUpdate BookUpdate
+	cancelAck  CancelAck
+	reject     Reject
+}
+
+// reset clears the union so a single field can be set.
+func (m *Message) reset() {
+	m.Ack = nil
+	m.Trade = nil
+	m.BookUpdate = nil
+	m.CancelAck = nil
+	m.Reject = nil
+}
+
+func (m *Message) setAck() *Ack {
+	m.reset()
+	m.ack = Ack{}
+	m.Ack = &m.ack
+	return m.Ack
+}
+
+func (m *Message) setTrade() *Trade {
+	m.reset()
+	m.trade = Trade{}
+	m.Trade = &m.trade
+	return m.Trade
+}
+
+func (m *Message) setBookUpdate() *BookUpdate {
+	m.reset()
+	m.bookUpdate = BookUpdate{}
+	m.BookUpdate = &m.bookUpdate
+	return m.BookUpdate
+}
+
+func (m *Message) setCancelAck() *CancelAck {
+	m.reset()
+	m.cancelAck = CancelAck{}
+	m.CancelAck = &m.cancelAck
+	return m.CancelAck
+}
+
+func (m *Message) setReject() *Reject {
+	m.reset()
+	m.reject = Reject{}
+	m.Reject = &m.reject
+	return m.Reject
 }
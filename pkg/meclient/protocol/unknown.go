@@ -0,0 +1,48 @@
+// Full path: pkg/meclient/protocol/unknown.go
+
+package protocol
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownMessageType matches any UnknownMessageError via errors.Is.
+var ErrUnknownMessageType = errors.New("unknown message type")
+
+// RawMessage is an undecoded inbound frame.
+type RawMessage struct {
+	Format  Format // Encoding the frame was read with
+	Type    string // CSV type field, or the binary type byte
+	Payload []byte // Frame payload without the length prefix
+}
+
+// UnknownMessageError is returned by decoders for a well-framed message
+// whose type they do not recognize. The frame has been fully consumed,
+// so the caller may keep decoding from the same stream.
+type UnknownMessageError struct {
+	Raw RawMessage
+}
+
+func (e *UnknownMessageError) Error() string {
+	if e.Raw.Format == FormatBinary && len(e.Raw.Type) == 1 {
+		return fmt.Sprintf("binary: %s: 0x%02x", ErrUnknownMessageType, e.Raw.Type[0])
+	}
+	return fmt.Sprintf("%s: %s", ErrUnknownMessageType, e.Raw.Type)
+}
+
+// Unwrap allows errors.Is(err, ErrUnknownMessageType).
+func (e *UnknownMessageError) Unwrap() error {
+	return ErrUnknownMessageType
+}
+
+// newUnknownMessage copies payload out of the decoder's frame buffer.
+func newUnknownMessage(format Format, msgType, payload []byte) *UnknownMessageError {
+	return &UnknownMessageError{
+		Raw: RawMessage{
+			Format:  format,
+			Type:    string(msgType),
+			Payload: append([]byte(nil), payload...),
+		},
+	}
+}
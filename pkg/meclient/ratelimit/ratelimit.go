@@ -0,0 +1,289 @@
+// Full path: pkg/meclient/ratelimit/ratelimit.go
+
+// Package ratelimit provides token-bucket rate limiting for outbound
+// messages.
+//
+// A Limiter holds up to four buckets per message: global orders/sec,
+// global messages/sec, and the same two per user ID. An order takes a
+// token from every order and message bucket, a cancel from the message
+// buckets only, and a flush (which has no user) from the global message
+// bucket only. Limiter is safe for concurrent use.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Mode selects what happens when a message would exceed a limit.
+type Mode int
+
+const (
+	ModeBlock  Mode = iota // Wait until tokens are available (default)
+	ModeReject             // Fail immediately with ErrRateLimited
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeBlock:
+		return "block"
+	case ModeReject:
+		return "reject"
+	default:
+		return "unknown"
+	}
+}
+
+// Class is the kind of outbound message being limited.
+type Class uint8
+
+const (
+	ClassOrder  Class = iota // Counts against order and message limits
+	ClassCancel              // Counts against message limits
+	ClassFlush               // Counts against the global message limit
+)
+
+// Errors
+var (
+	ErrRateLimited  = errors.New("rate limit exceeded")
+	ErrInvalidLimit = errors.New("invalid rate limit")
+)
+
+// Limits configures the limiter. A zero rate disables that bucket.
+type Limits struct {
+	OrdersPerSec       float64 // New orders, all users
+	MessagesPerSec     float64 // Orders, cancels and flushes, all users
+	UserOrdersPerSec   float64 // New orders, each user
+	UserMessagesPerSec float64 // Orders and cancels, each user
+
+	// Burst is the bucket size in messages. Zero allows one second's
+	// worth of each rate, and at least one message.
+	Burst int
+
+	Mode Mode
+}
+
+// Validate checks the limits for validity.
+func (l *Limits) Validate() error {
+	if l.OrdersPerSec < 0 || l.MessagesPerSec < 0 || l.UserOrdersPerSec < 0 || l.UserMessagesPerSec < 0 {
+		return ErrInvalidLimit
+	}
+	if l.Burst < 0 {
+		return ErrInvalidLimit
+	}
+	if l.Mode < ModeBlock || l.Mode > ModeReject {
+		return ErrInvalidLimit
+	}
+	return nil
+}
+
+// Stats counts limiter outcomes.
+type Stats struct {
+	Delayed  uint64 // Messages that had to wait for tokens
+	Rejected uint64 // Messages refused under ModeReject
+}
+
+// bucket is a token bucket. tokens may go negative while reservations
+// are waiting.
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rate float64, burst int, now time.Time) *bucket {
+	if rate <= 0 {
+		return nil
+	}
+	size := float64(burst)
+	if burst == 0 {
+		size = rate
+	}
+	if size < 1 {
+		size = 1
+	}
+	return &bucket{rate: rate, burst: size, tokens: size, last: now}
+}
+
+// advance adds the tokens earned since the last call.
+func (b *bucket) advance(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+}
+
+// wait returns how long until one more token is available.
+func (b *bucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// Limiter rate-limits outbound messages.
+type Limiter struct {
+	mu     sync.Mutex
+	limits Limits
+
+	orders       *bucket
+	messages     *bucket
+	userOrders   map[uint32]*bucket
+	userMessages map[uint32]*bucket
+
+	stats Stats
+	now   func() time.Time
+}
+
+// New creates a limiter. It returns ErrInvalidLimit if limits do not
+// validate.
+func New(limits Limits) (*Limiter, error) {
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+
+	l := &Limiter{
+		limits:       limits,
+		userOrders:   make(map[uint32]*bucket),
+		userMessages: make(map[uint32]*bucket),
+		now:          time.Now,
+	}
+	now := l.now()
+	l.orders = newBucket(limits.OrdersPerSec, limits.Burst, now)
+	l.messages = newBucket(limits.MessagesPerSec, limits.Burst, now)
+	return l, nil
+}
+
+// Limits returns the limiter's configuration.
+func (l *Limiter) Limits() Limits {
+	return l.limits
+}
+
+// Stats returns the limiter's counters.
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// Reserve takes the tokens for one message and returns how long the
+// caller must wait before sending it. Under ModeReject it takes nothing
+// and returns ErrRateLimited if the message cannot go now. A caller that
+// gives up waiting should call Unreserve.
+func (l *Limiter) Reserve(class Class, userID uint32) (time.Duration, error) {
+	return l.reserve(class, userID, l.limits.Mode)
+}
+
+// ReserveWait is Reserve under ModeBlock whatever the configured mode, for
+// messages that must not be refused.
+func (l *Limiter) ReserveWait(class Class, userID uint32) time.Duration {
+	delay, _ := l.reserve(class, userID, ModeBlock)
+	return delay
+}
+
+func (l *Limiter) reserve(class Class, userID uint32, mode Mode) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var buckets [4]*bucket
+	n := l.buckets(&buckets, class, userID)
+	now := l.now()
+
+	var delay time.Duration
+	for _, b := range buckets[:n] {
+		b.advance(now)
+		if d := b.wait(); d > delay {
+			delay = d
+		}
+	}
+
+	if delay > 0 && mode == ModeReject {
+		l.stats.Rejected++
+		return 0, ErrRateLimited
+	}
+
+	for _, b := range buckets[:n] {
+		b.tokens--
+	}
+	if delay > 0 {
+		l.stats.Delayed++
+	}
+	return delay, nil
+}
+
+// Unreserve returns the tokens taken by a Reserve whose message was not
+// sent.
+func (l *Limiter) Unreserve(class Class, userID uint32) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var buckets [4]*bucket
+	n := l.buckets(&buckets, class, userID)
+	for _, b := range buckets[:n] {
+		if b.tokens++; b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+}
+
+// Wait reserves tokens for one message and sleeps until it may be sent
+// or ctx ends.
+func (l *Limiter) Wait(ctx context.Context, class Class, userID uint32) error {
+	delay, err := l.Reserve(class, userID)
+	if err != nil || delay <= 0 {
+		return err
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.Unreserve(class, userID)
+		return ctx.Err()
+	}
+}
+
+// buckets collects the buckets a message draws from. Caller must hold l.mu.
+func (l *Limiter) buckets(dst *[4]*bucket, class Class, userID uint32) int {
+	n := 0
+	add := func(b *bucket) {
+		if b != nil {
+			dst[n] = b
+			n++
+		}
+	}
+
+	add(l.messages)
+	if class == ClassFlush {
+		return n
+	}
+	add(l.user(l.userMessages, l.limits.UserMessagesPerSec, userID))
+
+	if class == ClassOrder {
+		add(l.orders)
+		add(l.user(l.userOrders, l.limits.UserOrdersPerSec, userID))
+	}
+	return n
+}
+
+// user returns userID's bucket in m, creating it. Caller must hold l.mu.
+func (l *Limiter) user(m map[uint32]*bucket, rate float64, userID uint32) *bucket {
+	if rate <= 0 {
+		return nil
+	}
+	b, ok := m[userID]
+	if !ok {
+		b = newBucket(rate, l.limits.Burst, l.now())
+		m[userID] = b
+	}
+	return b
+}
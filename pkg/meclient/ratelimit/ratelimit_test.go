@@ -0,0 +1,198 @@
+// Full path: pkg/meclient/ratelimit/ratelimit_test.go
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLimiter(t *testing.T, limits Limits) (*Limiter, *fakeClock) {
+	t.Helper()
+
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	l, err := New(limits)
+	if err != nil {
+		t.Fatalf("failed to create limiter: %v", err)
+	}
+	l.now = clock.now
+	now := clock.now()
+	for _, b := range []*bucket{l.orders, l.messages} {
+		if b != nil {
+			b.last = now
+		}
+	}
+	return l, clock
+}
+
+func TestLimiter_BurstThenDelay(t *testing.T) {
+	l, clock := newTestLimiter(t, Limits{OrdersPerSec: 10})
+
+	for i := 0; i < 10; i++ {
+		if d, err := l.Reserve(ClassOrder, 1); err != nil || d != 0 {
+			t.Fatalf("order %d: expected no delay, got %v, %v", i, d, err)
+		}
+	}
+
+	d, err := l.Reserve(ClassOrder, 1)
+	if err != nil || d != 100*time.Millisecond {
+		t.Fatalf("expected 100ms delay, got %v, %v", d, err)
+	}
+
+	// The waiting reservation consumed the next token
+	clock.advance(100 * time.Millisecond)
+	if d, _ := l.Reserve(ClassOrder, 1); d != 100*time.Millisecond {
+		t.Errorf("expected 100ms delay, got %v", d)
+	}
+
+	if st := l.Stats(); st.Delayed != 2 || st.Rejected != 0 {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+}
+
+func TestLimiter_Reject(t *testing.T) {
+	l, clock := newTestLimiter(t, Limits{MessagesPerSec: 2, Mode: ModeReject})
+
+	_, _ = l.Reserve(ClassCancel, 1)
+	_, _ = l.Reserve(ClassFlush, 0)
+	if _, err := l.Reserve(ClassOrder, 1); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+
+	clock.advance(500 * time.Millisecond)
+	if _, err := l.Reserve(ClassOrder, 1); err != nil {
+		t.Errorf("expected a token after refill, got %v", err)
+	}
+	if st := l.Stats(); st.Rejected != 1 {
+		t.Errorf("expected 1 rejected, got %+v", st)
+	}
+}
+
+func TestLimiter_ReserveWait(t *testing.T) {
+	l, _ := newTestLimiter(t, Limits{MessagesPerSec: 2, Mode: ModeReject})
+
+	_, _ = l.Reserve(ClassCancel, 1)
+	_, _ = l.Reserve(ClassCancel, 1)
+
+	// Refused under ModeReject, but ReserveWait queues behind the bucket
+	if _, err := l.Reserve(ClassCancel, 1); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	if d := l.ReserveWait(ClassCancel, 1); d != 500*time.Millisecond {
+		t.Errorf("expected 500ms delay, got %v", d)
+	}
+	if st := l.Stats(); st.Rejected != 1 || st.Delayed != 1 {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+}
+
+func TestLimiter_PerUser(t *testing.T) {
+	l, _ := newTestLimiter(t, Limits{UserOrdersPerSec: 1, Mode: ModeReject})
+
+	if _, err := l.Reserve(ClassOrder, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := l.Reserve(ClassOrder, 1); err != ErrRateLimited {
+		t.Errorf("expected user 1 limited, got %v", err)
+	}
+	if _, err := l.Reserve(ClassOrder, 2); err != nil {
+		t.Errorf("expected user 2 unaffected, got %v", err)
+	}
+
+	// Cancels and flushes do not count against order limits
+	if _, err := l.Reserve(ClassCancel, 1); err != nil {
+		t.Errorf("expected cancel to pass, got %v", err)
+	}
+	if _, err := l.Reserve(ClassFlush, 0); err != nil {
+		t.Errorf("expected flush to pass, got %v", err)
+	}
+}
+
+func TestLimiter_RejectTakesNothing(t *testing.T) {
+	l, _ := newTestLimiter(t, Limits{MessagesPerSec: 5, UserOrdersPerSec: 1, Mode: ModeReject})
+
+	_, _ = l.Reserve(ClassOrder, 1)
+	for i := 0; i < 3; i++ {
+		_, _ = l.Reserve(ClassOrder, 1) // refused by the user bucket
+	}
+
+	// The refused orders must not have drained the global bucket
+	for i := 0; i < 4; i++ {
+		if _, err := l.Reserve(ClassCancel, 2); err != nil {
+			t.Fatalf("cancel %d: expected global tokens left, got %v", i, err)
+		}
+	}
+}
+
+func TestLimiter_Burst(t *testing.T) {
+	l, _ := newTestLimiter(t, Limits{OrdersPerSec: 100, Burst: 3, Mode: ModeReject})
+
+	for i := 0; i < 3; i++ {
+		if _, err := l.Reserve(ClassOrder, 1); err != nil {
+			t.Fatalf("order %d: unexpected error: %v", i, err)
+		}
+	}
+	if _, err := l.Reserve(ClassOrder, 1); err != ErrRateLimited {
+		t.Errorf("expected burst of 3, got %v", err)
+	}
+}
+
+func TestLimiter_WaitCancelled(t *testing.T) {
+	l, err := New(Limits{OrdersPerSec: 1, Burst: 1})
+	if err != nil {
+		t.Fatalf("failed to create limiter: %v", err)
+	}
+
+	if err := l.Wait(context.Background(), ClassOrder, 1); err != nil {
+		t.Fatalf("expected first order to pass, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, ClassOrder, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	// The abandoned reservation was returned, so the wait is under a second again
+	d, _ := l.Reserve(ClassOrder, 1)
+	if d > time.Second {
+		t.Errorf("expected reservation returned, got delay %v", d)
+	}
+}
+
+func TestLimits_Validate(t *testing.T) {
+	tests := []Limits{
+		{OrdersPerSec: -1},
+		{UserMessagesPerSec: -1},
+		{Burst: -1},
+		{Mode: ModeReject + 1},
+	}
+	for _, limits := range tests {
+		if _, err := New(limits); !errors.Is(err, ErrInvalidLimit) {
+			t.Errorf("expected ErrInvalidLimit for %+v, got %v", limits, err)
+		}
+	}
+}
+
+func TestModeString(t *testing.T) {
+	tests := map[Mode]string{
+		ModeBlock:  "block",
+		ModeReject: "reject",
+		Mode(99):   "unknown",
+	}
+	for mode, want := range tests {
+		if got := mode.String(); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+}
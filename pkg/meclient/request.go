@@ -0,0 +1,207 @@
+// Full path: pkg/meclient/request.go
+
+package meclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+// Request errors
+var (
+	ErrRejected       = errors.New("rejected by server")
+	ErrRequestPending = errors.New("request already pending for this order")
+)
+
+// RejectError is returned by PlaceOrder and Cancel when the server
+// rejects the request. It matches ErrRejected via errors.Is.
+type RejectError struct {
+	Reject protocol.Reject
+}
+
+func (e *RejectError) Error() string {
+	if e.Reject.Reason == "" {
+		return ErrRejected.Error()
+	}
+	return ErrRejected.Error() + ": " + e.Reject.Reason
+}
+
+// Unwrap allows errors.Is(err, ErrRejected).
+func (e *RejectError) Unwrap() error {
+	return ErrRejected
+}
+
+// requestKey correlates a response with its request. Order IDs are
+// scoped to a user.
+type requestKey struct {
+	userID  uint32
+	orderID uint32
+}
+
+// reply is the response delivered to a waiting request.
+type reply struct {
+	ack       protocol.Ack
+	cancelAck protocol.CancelAck
+	reject    *protocol.Reject
+}
+
+// pendingRequests tracks synchronous requests awaiting a response.
+// Each waiter channel has room for one reply so resolve never blocks
+// the read loop.
+type pendingRequests struct {
+	count int32 // waiters in both maps, read without the lock
+
+	mu      sync.Mutex
+	orders  map[requestKey]chan reply
+	cancels map[requestKey]chan reply
+}
+
+func newPendingRequests() *pendingRequests {
+	return &pendingRequests{
+		orders:  make(map[requestKey]chan reply),
+		cancels: make(map[requestKey]chan reply),
+	}
+}
+
+func (p *pendingRequests) add(m map[requestKey]chan reply, key requestKey) (chan reply, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, exists := m[key]; exists {
+		return nil, ErrRequestPending
+	}
+	ch := make(chan reply, 1)
+	m[key] = ch
+	atomic.AddInt32(&p.count, 1)
+	return ch, nil
+}
+
+func (p *pendingRequests) remove(m map[requestKey]chan reply, key requestKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := m[key]; ok {
+		delete(m, key)
+		atomic.AddInt32(&p.count, -1)
+	}
+}
+
+// take removes and returns the waiter for key, if any.
+func (p *pendingRequests) take(m map[requestKey]chan reply, key requestKey) (chan reply, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ch, ok := m[key]
+	if ok {
+		delete(m, key)
+		atomic.AddInt32(&p.count, -1)
+	}
+	return ch, ok
+}
+
+// resolve hands msg to the request waiting for it, if any. It takes no
+// lock when nothing is waiting.
+func (p *pendingRequests) resolve(msg *protocol.Message) {
+	if atomic.LoadInt32(&p.count) == 0 {
+		return
+	}
+
+	switch {
+	case msg.Ack != nil:
+		key := requestKey{userID: msg.Ack.UserID, orderID: msg.Ack.OrderID}
+		if ch, ok := p.take(p.orders, key); ok {
+			ch <- reply{ack: *msg.Ack}
+		}
+
+	case msg.CancelAck != nil:
+		key := requestKey{userID: msg.CancelAck.UserID, orderID: msg.CancelAck.OrderID}
+		if ch, ok := p.take(p.cancels, key); ok {
+			ch <- reply{cancelAck: *msg.CancelAck}
+		}
+
+	case msg.Reject != nil:
+		reject := *msg.Reject
+		key := requestKey{userID: reject.UserID, orderID: reject.OrderID}
+		if ch, ok := p.take(p.orders, key); ok {
+			ch <- reply{reject: &reject}
+		} else if ch, ok := p.take(p.cancels, key); ok {
+			ch <- reply{reject: &reject}
+		}
+	}
+}
+
+// PlaceOrder sends an order and waits for the server to acknowledge it.
+// A zero OrderID is filled in as for SendOrder; Ack.OrderID holds it. It
+// returns a *RejectError if the server rejects the order, or the
+// context's error if ctx ends first. The Ack is also delivered as
+// usual, through the channels, Events() or the Handler.
+func (c *Client) PlaceOrder(ctx context.Context, order protocol.NewOrder) (protocol.Ack, error) {
+	if err := protocol.ValidateOrder(&order); err != nil {
+		return protocol.Ack{}, err
+	}
+	if err := c.assignOrderID(&order); err != nil {
+		return protocol.Ack{}, err
+	}
+
+	key := requestKey{userID: order.UserID, orderID: order.OrderID}
+
+	ch, err := c.pending.add(c.pending.orders, key)
+	if err != nil {
+		return protocol.Ack{}, err
+	}
+
+	if _, err := c.SendOrderContext(ctx, order); err != nil {
+		c.pending.remove(c.pending.orders, key)
+		return protocol.Ack{}, err
+	}
+
+	r, err := c.awaitReply(ctx, c.pending.orders, key, ch)
+	if err != nil {
+		return protocol.Ack{}, err
+	}
+	return r.ack, nil
+}
+
+// Cancel sends a cancel request and waits for the server to confirm it.
+// It returns a *RejectError if the server rejects the cancel, or the
+// context's error if ctx ends first. The CancelAck is also delivered
+// as usual, through the channels, Events() or the Handler.
+func (c *Client) Cancel(ctx context.Context, cancel protocol.CancelOrder) (protocol.CancelAck, error) {
+	key := requestKey{userID: cancel.UserID, orderID: cancel.OrderID}
+
+	ch, err := c.pending.add(c.pending.cancels, key)
+	if err != nil {
+		return protocol.CancelAck{}, err
+	}
+
+	if err := c.SendCancelContext(ctx, cancel); err != nil {
+		c.pending.remove(c.pending.cancels, key)
+		return protocol.CancelAck{}, err
+	}
+
+	r, err := c.awaitReply(ctx, c.pending.cancels, key, ch)
+	if err != nil {
+		return protocol.CancelAck{}, err
+	}
+	return r.cancelAck, nil
+}
+
+func (c *Client) awaitReply(ctx context.Context, m map[requestKey]chan reply, key requestKey, ch chan reply) (reply, error) {
+	select {
+	case r := <-ch:
+		if r.reject != nil {
+			return reply{}, &RejectError{Reject: *r.reject}
+		}
+		return r, nil
+	case <-ctx.Done():
+		c.pending.remove(m, key)
+		return reply{}, ctx.Err()
+	case <-c.ctx.Done():
+		c.pending.remove(m, key)
+		return reply{}, ErrClientClosed
+	}
+}
@@ -0,0 +1,135 @@
+// Full path: pkg/meclient/request_test.go
+
+package meclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClient_PlaceOrder(t *testing.T) {
+	client := newTestClient(t, startTestServer(t, engineReply).addr, nil)
+	mustConnect(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	order := NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 10, Side: SideBuy, OrderID: 1001}
+	ack, err := client.PlaceOrder(ctx, order)
+	if err != nil {
+		t.Fatalf("place order error: %v", err)
+	}
+	if ack != (Ack{Symbol: "IBM", UserID: 1, OrderID: 1001}) {
+		t.Errorf("unexpected ack: %+v", ack)
+	}
+
+	// The channel API still sees the ack
+	select {
+	case <-client.Acks():
+	case <-time.After(time.Second):
+		t.Error("ack not delivered on Acks()")
+	}
+}
+
+func TestClient_PlaceOrder_Rejected(t *testing.T) {
+	client := newTestClient(t, startTestServer(t, engineReply).addr, nil)
+	mustConnect(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	order := NewOrder{UserID: 1, Symbol: "REJ", Price: 100, Qty: 10, Side: SideBuy, OrderID: 7}
+	_, err := client.PlaceOrder(ctx, order)
+	if !errors.Is(err, ErrRejected) {
+		t.Fatalf("expected ErrRejected, got %v", err)
+	}
+
+	var rejectErr *RejectError
+	if !errors.As(err, &rejectErr) || rejectErr.Reject.Reason != "symbol halted" {
+		t.Errorf("unexpected reject error: %v", err)
+	}
+}
+
+func TestClient_Cancel(t *testing.T) {
+	client := newTestClient(t, startTestServer(t, engineReply).addr, nil)
+	mustConnect(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	cancelAck, err := client.Cancel(ctx, CancelOrder{Symbol: "IBM", UserID: 1, OrderID: 1001})
+	if err != nil {
+		t.Fatalf("cancel error: %v", err)
+	}
+	if cancelAck.UserID != 1 || cancelAck.OrderID != 1001 {
+		t.Errorf("unexpected cancel ack: %+v", cancelAck)
+	}
+}
+
+func TestClient_PlaceOrder_ContextDeadline(t *testing.T) {
+	client := newTestClient(t, startTestServer(t, engineReply).addr, nil)
+	mustConnect(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	order := NewOrder{UserID: 1, Symbol: "SLOW", Price: 100, Qty: 10, Side: SideBuy, OrderID: 9}
+	if _, err := client.PlaceOrder(ctx, order); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	client.pending.mu.Lock()
+	n := len(client.pending.orders)
+	client.pending.mu.Unlock()
+	if n != 0 {
+		t.Errorf("expected waiter to be removed, %d remain", n)
+	}
+}
+
+func TestClient_PlaceOrder_Pending(t *testing.T) {
+	client, _ := New(DefaultConfig("localhost:1234"))
+	defer client.Close()
+
+	order := NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 10, Side: SideBuy, OrderID: 5}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = client.PlaceOrder(ctx, order)
+	}()
+
+	// Wait for the first request to register
+	deadline := time.Now().Add(time.Second)
+	for {
+		client.pending.mu.Lock()
+		n := len(client.pending.orders)
+		client.pending.mu.Unlock()
+		if n == 1 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := client.PlaceOrder(context.Background(), order); !errors.Is(err, ErrRequestPending) {
+		t.Errorf("expected ErrRequestPending, got %v", err)
+	}
+
+	cancel()
+	<-done
+}
+
+func TestClient_PlaceOrder_Invalid(t *testing.T) {
+	client, _ := New(DefaultConfig("localhost:1234"))
+	defer client.Close()
+
+	_, err := client.PlaceOrder(context.Background(), NewOrder{UserID: 1, Symbol: "", Qty: 10, Side: SideBuy})
+	if err != ErrEmptySymbol {
+		t.Errorf("expected ErrEmptySymbol, got %v", err)
+	}
+	if len(client.pending.orders) != 0 {
+		t.Error("expected no waiter after validation failure")
+	}
+}
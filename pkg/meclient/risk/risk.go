@@ -0,0 +1,445 @@
+// Full path: pkg/meclient/risk/risk.go
+
+// Package risk provides pre-trade risk checks.
+//
+// A Checker admits or refuses each outgoing order against configured
+// Limits. It keeps its own view of open orders, filled positions and last
+// trade prices, fed by the client's read loop, and is safe for concurrent
+// use.
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+// Check identifies a single risk check.
+type Check uint8
+
+const (
+	CheckOrderQty    Check = iota + 1 // Order quantity above MaxOrderQty
+	CheckNotional                     // Price × qty above MaxNotional
+	CheckPosition                     // Worst-case position above the symbol's limit
+	CheckOpenOrders                   // Open orders at MaxOpenOrders
+	CheckPriceCollar                  // Price too far from the last trade
+	CheckSymbol                       // Symbol not in the allow-list
+)
+
+func (c Check) String() string {
+	switch c {
+	case CheckOrderQty:
+		return "order_qty"
+	case CheckNotional:
+		return "notional"
+	case CheckPosition:
+		return "position"
+	case CheckOpenOrders:
+		return "open_orders"
+	case CheckPriceCollar:
+		return "price_collar"
+	case CheckSymbol:
+		return "symbol"
+	default:
+		return "unknown"
+	}
+}
+
+// Errors, one per check. A refused order returns an *Error that matches
+// the sentinel for the failed check via errors.Is.
+var (
+	ErrMaxOrderQty      = errors.New("risk: order quantity limit exceeded")
+	ErrMaxNotional      = errors.New("risk: notional limit exceeded")
+	ErrPositionLimit    = errors.New("risk: position limit exceeded")
+	ErrMaxOpenOrders    = errors.New("risk: open order limit reached")
+	ErrPriceCollar      = errors.New("risk: price outside collar")
+	ErrSymbolNotAllowed = errors.New("risk: symbol not allowed")
+
+	ErrDuplicateOrder = errors.New("risk: order already open")
+)
+
+// Error describes why an order was refused.
+type Error struct {
+	Check  Check
+	Symbol string
+	Value  uint64 // Offending value: qty, notional, exposure, count or price
+	Limit  uint64 // Limit it broke; for collars the violated band edge
+}
+
+func (e *Error) Error() string {
+	switch e.Check {
+	case CheckSymbol:
+		return fmt.Sprintf("%v: %s", e.Unwrap(), e.Symbol)
+	case CheckPriceCollar:
+		return fmt.Sprintf("%v: %s price %d beyond %d", e.Unwrap(), e.Symbol, e.Value, e.Limit)
+	default:
+		return fmt.Sprintf("%v: %s %d > %d", e.Unwrap(), e.Symbol, e.Value, e.Limit)
+	}
+}
+
+// Unwrap returns the sentinel error for the failed check.
+func (e *Error) Unwrap() error {
+	switch e.Check {
+	case CheckOrderQty:
+		return ErrMaxOrderQty
+	case CheckNotional:
+		return ErrMaxNotional
+	case CheckPosition:
+		return ErrPositionLimit
+	case CheckOpenOrders:
+		return ErrMaxOpenOrders
+	case CheckPriceCollar:
+		return ErrPriceCollar
+	case CheckSymbol:
+		return ErrSymbolNotAllowed
+	default:
+		return nil
+	}
+}
+
+// Limits configures the checks. A zero field disables its check.
+type Limits struct {
+	MaxOrderQty    uint32            // Per-order quantity
+	MaxNotional    uint64            // Per-order price × qty; market orders use the last trade price
+	MaxPosition    uint64            // Worst-case long or short position per symbol
+	PositionLimits map[string]uint64 // Per-symbol overrides of MaxPosition
+	MaxOpenOrders  int               // Orders sent and not yet filled, cancelled or rejected
+	PriceCollarBps uint32            // Max distance from the last trade, in basis points
+	Symbols        []string          // Allow-list; empty allows every symbol
+}
+
+// Stats counts refused orders per check.
+type Stats struct {
+	OrderQty    uint64
+	Notional    uint64
+	Position    uint64
+	OpenOrders  uint64
+	PriceCollar uint64
+	Symbol      uint64
+}
+
+// Total returns the number of refused orders.
+func (s Stats) Total() uint64 {
+	return s.OrderQty + s.Notional + s.Position + s.OpenOrders + s.PriceCollar + s.Symbol
+}
+
+type key struct {
+	userID  uint32
+	orderID uint32
+}
+
+// openOrder is the unfilled part of an admitted order.
+type openOrder struct {
+	symbol    string
+	side      protocol.Side
+	remaining uint32
+	acked     bool // Acked or filled, so a later reject is of a cancel
+}
+
+// exposure is the per-symbol state the position check needs.
+type exposure struct {
+	position  int64  // Filled buys minus filled sells
+	openBuys  uint64 // Unfilled buy quantity
+	openSells uint64 // Unfilled sell quantity
+	lastPrice uint32 // Last trade price; 0 if none seen
+}
+
+// Checker applies Limits to outgoing orders.
+type Checker struct {
+	mu      sync.Mutex
+	limits  Limits
+	allowed map[string]struct{}
+	open    map[key]openOrder
+	symbols map[string]*exposure
+	stats   Stats
+}
+
+// NewChecker creates a checker with the given limits.
+func NewChecker(limits Limits) *Checker {
+	c := &Checker{
+		open:    make(map[key]openOrder),
+		symbols: make(map[string]*exposure),
+	}
+	c.SetLimits(limits)
+	return c
+}
+
+// SetLimits replaces the limits. Open orders and positions are kept.
+func (c *Checker) SetLimits(limits Limits) {
+	var allowed map[string]struct{}
+	if len(limits.Symbols) > 0 {
+		allowed = make(map[string]struct{}, len(limits.Symbols))
+		for _, s := range limits.Symbols {
+			allowed[s] = struct{}{}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.limits = limits
+	c.allowed = allowed
+}
+
+// Limits returns the current limits.
+func (c *Checker) Limits() Limits {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limits
+}
+
+// Admit checks order against the limits and, if it passes, records it as
+// open. It returns an *Error naming the first failed check, or
+// ErrDuplicateOrder if an order with the same key is already open.
+func (c *Checker) Admit(order protocol.NewOrder) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := key{userID: order.UserID, orderID: order.OrderID}
+	if _, ok := c.open[k]; ok {
+		return ErrDuplicateOrder
+	}
+
+	if err := c.check(&order); err != nil {
+		c.count(err.Check)
+		return err
+	}
+
+	c.open[k] = openOrder{symbol: order.Symbol, side: order.Side, remaining: order.Qty}
+	ex := c.exposure(order.Symbol)
+	if order.Side == protocol.SideBuy {
+		ex.openBuys += uint64(order.Qty)
+	} else {
+		ex.openSells += uint64(order.Qty)
+	}
+	return nil
+}
+
+// Release forgets an admitted order, e.g. when it could not be sent.
+func (c *Checker) Release(userID, orderID uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.release(key{userID: userID, orderID: orderID})
+}
+
+// ApplyTrade records the last trade price and fills against either side.
+func (c *Checker) ApplyTrade(trade protocol.Trade) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.exposure(trade.Symbol).lastPrice = trade.Price
+	c.fill(key{userID: trade.BuyUserID, orderID: trade.BuyOrderID}, trade.Qty)
+	c.fill(key{userID: trade.SellUserID, orderID: trade.SellOrderID}, trade.Qty)
+}
+
+// ApplyAck marks an admitted order as live on the engine.
+func (c *Checker) ApplyAck(ack protocol.Ack) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := key{userID: ack.UserID, orderID: ack.OrderID}
+	if o, ok := c.open[k]; ok {
+		o.acked = true
+		c.open[k] = o
+	}
+}
+
+// ApplyCancelAck releases a cancelled order's open quantity.
+func (c *Checker) ApplyCancelAck(cancelAck protocol.CancelAck) {
+	c.Release(cancelAck.UserID, cancelAck.OrderID)
+}
+
+// ApplyReject releases a rejected order's open quantity. A reject naming
+// an order already acked or filled refuses a cancel, not the order, so
+// its quantity stays open.
+func (c *Checker) ApplyReject(reject protocol.Reject) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := key{userID: reject.UserID, orderID: reject.OrderID}
+	if o, ok := c.open[k]; ok && !o.acked {
+		c.release(k)
+	}
+}
+
+// Position returns the filled position in symbol; negative is short.
+func (c *Checker) Position(symbol string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ex, ok := c.symbols[symbol]; ok {
+		return ex.position
+	}
+	return 0
+}
+
+// LastPrice returns the last trade price seen for symbol.
+func (c *Checker) LastPrice(symbol string) (uint32, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ex, ok := c.symbols[symbol]; ok && ex.lastPrice != 0 {
+		return ex.lastPrice, true
+	}
+	return 0, false
+}
+
+// OpenCount returns the number of admitted orders still open.
+func (c *Checker) OpenCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.open)
+}
+
+// Stats returns the refused order counts.
+func (c *Checker) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// check runs every enabled check in order. Caller must hold c.mu.
+func (c *Checker) check(order *protocol.NewOrder) *Error {
+	l := &c.limits
+
+	if c.allowed != nil {
+		if _, ok := c.allowed[order.Symbol]; !ok {
+			return &Error{Check: CheckSymbol, Symbol: order.Symbol}
+		}
+	}
+
+	if l.MaxOrderQty > 0 && order.Qty > l.MaxOrderQty {
+		return &Error{Check: CheckOrderQty, Symbol: order.Symbol, Value: uint64(order.Qty), Limit: uint64(l.MaxOrderQty)}
+	}
+
+	var last uint32
+	ex, ok := c.symbols[order.Symbol]
+	if ok {
+		last = ex.lastPrice
+	}
+
+	if l.MaxNotional > 0 {
+		price := order.Price
+		if price == 0 {
+			price = last
+		}
+		if notional := uint64(price) * uint64(order.Qty); notional > l.MaxNotional {
+			return &Error{Check: CheckNotional, Symbol: order.Symbol, Value: notional, Limit: l.MaxNotional}
+		}
+	}
+
+	if l.PriceCollarBps > 0 && order.Price > 0 && last > 0 {
+		band := uint64(last) * uint64(l.PriceCollarBps) / 10000
+		if hi := uint64(last) + band; uint64(order.Price) > hi {
+			return &Error{Check: CheckPriceCollar, Symbol: order.Symbol, Value: uint64(order.Price), Limit: hi}
+		}
+		if band <= uint64(last) {
+			if lo := uint64(last) - band; uint64(order.Price) < lo {
+				return &Error{Check: CheckPriceCollar, Symbol: order.Symbol, Value: uint64(order.Price), Limit: lo}
+			}
+		}
+	}
+
+	if limit := c.positionLimit(order.Symbol); limit > 0 {
+		var worst int64
+		if ok {
+			if order.Side == protocol.SideBuy {
+				worst = ex.position + int64(ex.openBuys)
+			} else {
+				worst = int64(ex.openSells) - ex.position
+			}
+		}
+		worst += int64(order.Qty)
+		if worst > 0 && uint64(worst) > limit {
+			return &Error{Check: CheckPosition, Symbol: order.Symbol, Value: uint64(worst), Limit: limit}
+		}
+	}
+
+	if l.MaxOpenOrders > 0 && len(c.open) >= l.MaxOpenOrders {
+		return &Error{Check: CheckOpenOrders, Symbol: order.Symbol, Value: uint64(len(c.open) + 1), Limit: uint64(l.MaxOpenOrders)}
+	}
+
+	return nil
+}
+
+// positionLimit returns the limit for symbol. Caller must hold c.mu.
+func (c *Checker) positionLimit(symbol string) uint64 {
+	if limit, ok := c.limits.PositionLimits[symbol]; ok {
+		return limit
+	}
+	return c.limits.MaxPosition
+}
+
+// count records a refused order. Caller must hold c.mu.
+func (c *Checker) count(check Check) {
+	switch check {
+	case CheckOrderQty:
+		c.stats.OrderQty++
+	case CheckNotional:
+		c.stats.Notional++
+	case CheckPosition:
+		c.stats.Position++
+	case CheckOpenOrders:
+		c.stats.OpenOrders++
+	case CheckPriceCollar:
+		c.stats.PriceCollar++
+	case CheckSymbol:
+		c.stats.Symbol++
+	}
+}
+
+// exposure returns the state for symbol, creating it. Caller must hold c.mu.
+func (c *Checker) exposure(symbol string) *exposure {
+	ex, ok := c.symbols[symbol]
+	if !ok {
+		ex = &exposure{}
+		c.symbols[symbol] = ex
+	}
+	return ex
+}
+
+// fill moves filled quantity from open to position. Caller must hold c.mu.
+func (c *Checker) fill(k key, qty uint32) {
+	o, ok := c.open[k]
+	if !ok {
+		return
+	}
+	if qty > o.remaining {
+		qty = o.remaining
+	}
+
+	ex := c.exposure(o.symbol)
+	if o.side == protocol.SideBuy {
+		ex.position += int64(qty)
+		ex.openBuys -= uint64(qty)
+	} else {
+		ex.position -= int64(qty)
+		ex.openSells -= uint64(qty)
+	}
+
+	o.remaining -= qty
+	o.acked = true
+	if o.remaining == 0 {
+		delete(c.open, k)
+	} else {
+		c.open[k] = o
+	}
+}
+
+// release drops an open order and its unfilled quantity. Caller must hold c.mu.
+func (c *Checker) release(k key) {
+	o, ok := c.open[k]
+	if !ok {
+		return
+	}
+	delete(c.open, k)
+
+	ex := c.exposure(o.symbol)
+	if o.side == protocol.SideBuy {
+		ex.openBuys -= uint64(o.remaining)
+	} else {
+		ex.openSells -= uint64(o.remaining)
+	}
+}
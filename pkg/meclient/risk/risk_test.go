@@ -0,0 +1,201 @@
+// Full path: pkg/meclient/risk/risk_test.go
+
+package risk
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+func order(orderID uint32, symbol string, side protocol.Side, price, qty uint32) protocol.NewOrder {
+	return protocol.NewOrder{
+		UserID:  1,
+		OrderID: orderID,
+		Symbol:  symbol,
+		Side:    side,
+		Price:   price,
+		Qty:     qty,
+	}
+}
+
+func expectCheck(t *testing.T, err error, sentinel error, check Check) {
+	t.Helper()
+
+	if !errors.Is(err, sentinel) {
+		t.Fatalf("expected %v, got %v", sentinel, err)
+	}
+	var riskErr *Error
+	if !errors.As(err, &riskErr) || riskErr.Check != check {
+		t.Errorf("expected *Error with check %v, got %#v", check, err)
+	}
+}
+
+func TestChecker_OrderQty(t *testing.T) {
+	c := NewChecker(Limits{MaxOrderQty: 100})
+
+	if err := c.Admit(order(1, "IBM", protocol.SideBuy, 10, 100)); err != nil {
+		t.Fatalf("expected order at the limit to pass, got %v", err)
+	}
+	err := c.Admit(order(2, "IBM", protocol.SideBuy, 10, 101))
+	expectCheck(t, err, ErrMaxOrderQty, CheckOrderQty)
+}
+
+func TestChecker_Notional(t *testing.T) {
+	c := NewChecker(Limits{MaxNotional: 10000})
+
+	if err := c.Admit(order(1, "IBM", protocol.SideBuy, 100, 100)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectCheck(t, c.Admit(order(2, "IBM", protocol.SideBuy, 101, 100)), ErrMaxNotional, CheckNotional)
+
+	// Market orders are valued at the last trade
+	if err := c.Admit(order(3, "IBM", protocol.SideBuy, 0, 1000)); err != nil {
+		t.Errorf("expected market order with no last trade to pass, got %v", err)
+	}
+	c.ApplyTrade(protocol.Trade{Symbol: "IBM", Price: 50, Qty: 1})
+	expectCheck(t, c.Admit(order(4, "IBM", protocol.SideBuy, 0, 1000)), ErrMaxNotional, CheckNotional)
+}
+
+func TestChecker_Position(t *testing.T) {
+	c := NewChecker(Limits{MaxPosition: 100, PositionLimits: map[string]uint64{"AAPL": 10}})
+
+	if err := c.Admit(order(1, "IBM", protocol.SideBuy, 10, 60)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Open buys count toward the long side
+	expectCheck(t, c.Admit(order(2, "IBM", protocol.SideBuy, 10, 50)), ErrPositionLimit, CheckPosition)
+
+	// A fill moves quantity from open to position without changing exposure
+	c.ApplyTrade(protocol.Trade{Symbol: "IBM", BuyUserID: 1, BuyOrderID: 1, SellUserID: 2, SellOrderID: 1, Price: 10, Qty: 60})
+	if pos := c.Position("IBM"); pos != 60 {
+		t.Errorf("expected position 60, got %d", pos)
+	}
+	expectCheck(t, c.Admit(order(2, "IBM", protocol.SideBuy, 10, 50)), ErrPositionLimit, CheckPosition)
+
+	// Selling against a long is well within the short limit
+	if err := c.Admit(order(3, "IBM", protocol.SideSell, 10, 150)); err != nil {
+		t.Errorf("expected sell to pass, got %v", err)
+	}
+
+	// Per-symbol override
+	expectCheck(t, c.Admit(order(4, "AAPL", protocol.SideSell, 10, 11)), ErrPositionLimit, CheckPosition)
+}
+
+func TestChecker_OpenOrders(t *testing.T) {
+	c := NewChecker(Limits{MaxOpenOrders: 2})
+
+	_ = c.Admit(order(1, "IBM", protocol.SideBuy, 10, 1))
+	_ = c.Admit(order(2, "IBM", protocol.SideBuy, 10, 1))
+	expectCheck(t, c.Admit(order(3, "IBM", protocol.SideBuy, 10, 1)), ErrMaxOpenOrders, CheckOpenOrders)
+
+	c.ApplyCancelAck(protocol.CancelAck{UserID: 1, OrderID: 1})
+	if err := c.Admit(order(3, "IBM", protocol.SideBuy, 10, 1)); err != nil {
+		t.Errorf("expected room after cancel, got %v", err)
+	}
+
+	c.ApplyReject(protocol.Reject{UserID: 1, OrderID: 2})
+	c.Release(1, 3)
+	if n := c.OpenCount(); n != 0 {
+		t.Errorf("expected no open orders, got %d", n)
+	}
+}
+
+func TestChecker_CancelReject(t *testing.T) {
+	c := NewChecker(Limits{MaxPosition: 25})
+
+	_ = c.Admit(order(1, "IBM", protocol.SideBuy, 100, 10))
+	_ = c.Admit(order(2, "IBM", protocol.SideBuy, 100, 10))
+	c.ApplyAck(protocol.Ack{UserID: 1, OrderID: 1})
+
+	// Order 1 is live, so its reject refuses a cancel; order 2 is not
+	c.ApplyReject(protocol.Reject{UserID: 1, OrderID: 1, Reason: "cancel refused"})
+	c.ApplyReject(protocol.Reject{UserID: 1, OrderID: 2, Reason: "price outside collar"})
+
+	if n := c.OpenCount(); n != 1 {
+		t.Errorf("expected the acked order still open, got %d open", n)
+	}
+	expectCheck(t, c.Admit(order(3, "IBM", protocol.SideBuy, 100, 20)), ErrPositionLimit, CheckPosition)
+}
+
+func TestChecker_PriceCollar(t *testing.T) {
+	c := NewChecker(Limits{PriceCollarBps: 500}) // 5%
+
+	if err := c.Admit(order(1, "IBM", protocol.SideBuy, 1000, 1)); err != nil {
+		t.Errorf("expected no collar before the first trade, got %v", err)
+	}
+
+	c.ApplyTrade(protocol.Trade{Symbol: "IBM", Price: 100, Qty: 1})
+	if err := c.Admit(order(2, "IBM", protocol.SideBuy, 105, 1)); err != nil {
+		t.Errorf("expected price at the band edge to pass, got %v", err)
+	}
+	expectCheck(t, c.Admit(order(3, "IBM", protocol.SideBuy, 106, 1)), ErrPriceCollar, CheckPriceCollar)
+
+	err := c.Admit(order(4, "IBM", protocol.SideSell, 94, 1))
+	expectCheck(t, err, ErrPriceCollar, CheckPriceCollar)
+	var riskErr *Error
+	if errors.As(err, &riskErr) && riskErr.Limit != 95 {
+		t.Errorf("expected lower band 95, got %d", riskErr.Limit)
+	}
+
+	if err := c.Admit(order(5, "IBM", protocol.SideSell, 0, 1)); err != nil {
+		t.Errorf("expected market order to skip the collar, got %v", err)
+	}
+}
+
+func TestChecker_Symbols(t *testing.T) {
+	c := NewChecker(Limits{Symbols: []string{"IBM"}})
+
+	if err := c.Admit(order(1, "IBM", protocol.SideBuy, 10, 1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectCheck(t, c.Admit(order(2, "AAPL", protocol.SideBuy, 10, 1)), ErrSymbolNotAllowed, CheckSymbol)
+
+	c.SetLimits(Limits{})
+	if err := c.Admit(order(2, "AAPL", protocol.SideBuy, 10, 1)); err != nil {
+		t.Errorf("expected empty allow-list to allow all, got %v", err)
+	}
+}
+
+func TestChecker_DuplicateAndStats(t *testing.T) {
+	c := NewChecker(Limits{MaxOrderQty: 10, Symbols: []string{"IBM"}})
+
+	_ = c.Admit(order(1, "IBM", protocol.SideBuy, 10, 1))
+	if err := c.Admit(order(1, "IBM", protocol.SideBuy, 10, 1)); err != ErrDuplicateOrder {
+		t.Errorf("expected ErrDuplicateOrder, got %v", err)
+	}
+
+	_ = c.Admit(order(2, "IBM", protocol.SideBuy, 10, 11))
+	_ = c.Admit(order(3, "IBM", protocol.SideBuy, 10, 11))
+	_ = c.Admit(order(4, "AAPL", protocol.SideBuy, 10, 1))
+
+	st := c.Stats()
+	if st.OrderQty != 2 || st.Symbol != 1 || st.Total() != 3 {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+}
+
+func TestErrorString(t *testing.T) {
+	err := &Error{Check: CheckOrderQty, Symbol: "IBM", Value: 500, Limit: 100}
+	if got := err.Error(); got != "risk: order quantity limit exceeded: IBM 500 > 100" {
+		t.Errorf("unexpected message: %q", got)
+	}
+}
+
+func TestCheckString(t *testing.T) {
+	tests := map[Check]string{
+		CheckOrderQty:    "order_qty",
+		CheckNotional:    "notional",
+		CheckPosition:    "position",
+		CheckOpenOrders:  "open_orders",
+		CheckPriceCollar: "price_collar",
+		CheckSymbol:      "symbol",
+		Check(0):         "unknown",
+	}
+	for check, want := range tests {
+		if got := check.String(); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+// Full path: pkg/meclient/send_test.go
+
+package meclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/orderid"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/risk"
+)
+
+// newFullQueueClient returns an unconnected client whose one-slot write
+// queue is already full, since no write loop is draining it.
+func newFullQueueClient(t *testing.T, mutate func(cfg *Config)) *Client {
+	t.Helper()
+
+	client := newTestClient(t, "localhost:1234", func(cfg *Config) {
+		cfg.ChannelBuffer = 1
+		if mutate != nil {
+			mutate(cfg)
+		}
+	})
+	if err := client.SendFlushContext(context.Background()); err != nil {
+		t.Fatalf("failed to fill queue: %v", err)
+	}
+	return client
+}
+
+var testOrder = NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 10, Side: SideBuy, OrderID: 1}
+
+func TestClient_SendOrder_QueueFull(t *testing.T) {
+	client := newFullQueueClient(t, nil)
+
+	if _, err := client.SendOrder(testOrder); err != ErrWriteQueueFull {
+		t.Errorf("expected ErrWriteQueueFull, got %v", err)
+	}
+}
+
+func TestClient_SendOrderContext_Deadline(t *testing.T) {
+	client := newFullQueueClient(t, trackOrders)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.SendOrderContext(ctx, testOrder); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if _, ok := client.Orders().Get(testOrder.UserID, testOrder.OrderID); ok {
+		t.Error("order should be untracked after a failed send")
+	}
+}
+
+func TestClient_SendOrderContext_WaitsForRoom(t *testing.T) {
+	client := newFullQueueClient(t, nil)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		<-client.writeCh
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if _, err := client.SendOrderContext(ctx, testOrder); err != nil {
+		t.Fatalf("expected send once room frees up, got %v", err)
+	}
+
+	req := <-client.writeCh
+	if req.reqType != writeRequestOrder || req.order.OrderID != testOrder.OrderID {
+		t.Errorf("unexpected queued request: %+v", req)
+	}
+}
+
+func TestClient_SendCancelContext_Canceled(t *testing.T) {
+	client := newFullQueueClient(t, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := client.SendCancelContext(ctx, CancelOrder{UserID: 1, OrderID: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+}
+
+func TestClient_SendCancel_QueueFullClearsPending(t *testing.T) {
+	client := newFullQueueClient(t, trackOrders)
+	_ = client.orders.Track(testOrder)
+	client.orders.ApplyAck(Ack{UserID: testOrder.UserID, OrderID: testOrder.OrderID})
+
+	err := client.SendCancel(CancelOrder{UserID: testOrder.UserID, OrderID: testOrder.OrderID})
+	if err != ErrWriteQueueFull {
+		t.Fatalf("expected ErrWriteQueueFull, got %v", err)
+	}
+	if o, _ := client.Orders().Get(testOrder.UserID, testOrder.OrderID); o.CancelPending {
+		t.Error("expected no cancel pending after a failed send")
+	}
+}
+
+func TestClient_BlockingSends(t *testing.T) {
+	client := newFullQueueClient(t, func(cfg *Config) { cfg.BlockingSends = true })
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.SendOrder(testOrder)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("SendOrder should block on a full queue, returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	client.Close()
+
+	select {
+	case err := <-done:
+		if err != ErrClientClosed {
+			t.Errorf("expected ErrClientClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SendOrder still blocked after Close")
+	}
+}
+
+func TestClient_PlaceOrder_QueueFullHonoursContext(t *testing.T) {
+	client := newFullQueueClient(t, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.PlaceOrder(ctx, testOrder); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func waitForBatches(t *testing.T, client *Client, want uint64) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for client.Stats().WriteBatches < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d write batches, got %d", want, client.Stats().WriteBatches)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := client.Stats().WriteBatches; got != want {
+		t.Errorf("expected %d write batches, got %d", want, got)
+	}
+}
+
+func TestClient_WriteBatching(t *testing.T) {
+	server := startTestServer(t, nil)
+
+	client := newTestClient(t, server.addr, func(cfg *Config) {
+		cfg.ChannelBuffer = 16
+		cfg.WriteBatchSize = 4
+	})
+
+	// Queue everything before the write loop starts so batches are full
+	want := make([]string, 0, 10)
+	for id := uint32(1); id <= 10; id++ {
+		order := testOrder
+		order.OrderID = id
+		if _, err := client.SendOrder(order); err != nil {
+			t.Fatalf("failed to queue order %d: %v", id, err)
+		}
+		want = append(want, fmt.Sprintf("N,1,IBM,100,10,B,%d", id))
+	}
+
+	mustConnect(t, client)
+
+	expectFrames(t, server.frames, want...)
+	waitForBatches(t, client, 3)
+}
+
+func TestClient_WriteLinger(t *testing.T) {
+	server := startTestServer(t, nil)
+
+	client := newTestClient(t, server.addr, func(cfg *Config) { cfg.WriteLinger = 200 * time.Millisecond })
+	mustConnect(t, client)
+
+	for id := uint32(1); id <= 3; id++ {
+		order := testOrder
+		order.OrderID = id
+		if _, err := client.SendOrder(order); err != nil {
+			t.Fatalf("failed to send order %d: %v", id, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	expectFrames(t, server.frames, "N,1,IBM,100,10,B,1", "N,1,IBM,100,10,B,2", "N,1,IBM,100,10,B,3")
+	waitForBatches(t, client, 1)
+}
+
+func TestClient_SendOrder_AssignsOrderID(t *testing.T) {
+	server := startTestServer(t, nil)
+
+	client := newTestClient(t, server.addr, func(cfg *Config) {
+		cfg.TrackOrders = true
+		cfg.OrderIDs = orderid.NewMonotonic(500)
+	})
+	mustConnect(t, client)
+
+	order := testOrder
+	order.OrderID = 0
+	explicit := testOrder
+	explicit.OrderID = 7
+
+	var ids []uint32
+	for _, o := range []NewOrder{order, explicit, order} {
+		id, err := client.SendOrder(o)
+		if err != nil {
+			t.Fatalf("failed to send order: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if ids[0] != 500 || ids[1] != 7 || ids[2] != 501 {
+		t.Errorf("expected IDs [500 7 501], got %v", ids)
+	}
+
+	expectFrames(t, server.frames, "N,1,IBM,100,10,B,500", "N,1,IBM,100,10,B,7", "N,1,IBM,100,10,B,501")
+
+	if _, ok := client.Orders().Get(1, 501); !ok {
+		t.Error("expected order tracked under its assigned ID")
+	}
+}
+
+func TestClient_DefaultOrderIDs(t *testing.T) {
+	client := newFullQueueClient(t, nil)
+
+	if client.OrderIDs() == nil {
+		t.Fatal("expected a default allocator")
+	}
+	order := testOrder
+	order.OrderID = 0
+	if id, err := client.SendOrder(order); err != ErrWriteQueueFull || id != 0 {
+		t.Errorf("expected 0, ErrWriteQueueFull on a full queue, got %d, %v", id, err)
+	}
+}
+
+func TestClient_SendOrder_Risk(t *testing.T) {
+	client := newFullQueueClient(t, func(cfg *Config) {
+		cfg.ChannelBuffer = 2
+		cfg.Risk = &RiskLimits{MaxOrderQty: 100, MaxOpenOrders: 10}
+	})
+
+	big := testOrder
+	big.Qty = 500
+	_, err := client.SendOrder(big)
+	if !errors.Is(err, risk.ErrMaxOrderQty) {
+		t.Fatalf("expected ErrMaxOrderQty, got %v", err)
+	}
+	var riskErr *RiskError
+	if !errors.As(err, &riskErr) || riskErr.Value != 500 || riskErr.Limit != 100 {
+		t.Errorf("unexpected risk error: %#v", err)
+	}
+
+	if _, err := client.SendOrder(testOrder); err != nil {
+		t.Fatalf("expected order within limits to queue, got %v", err)
+	}
+
+	// Queue is now full; the refused send must not hold risk capacity
+	next := testOrder
+	next.OrderID = 2
+	if _, err := client.SendOrder(next); err != ErrWriteQueueFull {
+		t.Fatalf("expected ErrWriteQueueFull, got %v", err)
+	}
+	if n := client.Risk().OpenCount(); n != 1 {
+		t.Errorf("expected 1 open order in risk, got %d", n)
+	}
+
+	if got := client.Stats().RiskRejects; got != 1 {
+		t.Errorf("expected RiskRejects=1, got %d", got)
+	}
+	if st := client.Risk().Stats(); st.OrderQty != 1 {
+		t.Errorf("unexpected risk stats: %+v", st)
+	}
+}
+
+func TestClient_RateLimit_Reject(t *testing.T) {
+	limits := RateLimits{OrdersPerSec: 2, Mode: RateLimitReject}
+	client := newTestClient(t, "localhost:1234", func(cfg *Config) { cfg.RateLimit = &limits })
+
+	for id := uint32(1); id <= 2; id++ {
+		order := testOrder
+		order.OrderID = id
+		if _, err := client.SendOrder(order); err != nil {
+			t.Fatalf("order %d: unexpected error: %v", id, err)
+		}
+	}
+	if _, err := client.SendOrder(testOrder); err != ErrRateLimited {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+
+	// Cancels are not order-limited
+	if err := client.SendCancel(CancelOrder{UserID: 1, OrderID: 1}); err != nil {
+		t.Errorf("expected cancel to pass, got %v", err)
+	}
+
+	snap := client.Stats()
+	if snap.RateLimited != 1 || snap.MessagesSent != 3 {
+		t.Errorf("unexpected stats: %+v", snap)
+	}
+}
+
+func TestClient_RateLimit_StaleCancels(t *testing.T) {
+	limits := RateLimits{MessagesPerSec: 20, Burst: 1, Mode: RateLimitReject}
+	client := newTestClient(t, "localhost:1234", func(cfg *Config) { cfg.RateLimit = &limits })
+
+	// Cancel-on-disconnect waits for tokens rather than being refused
+	stale := []TrackedOrder{
+		{UserID: 1, OrderID: 1, Symbol: "IBM"},
+		{UserID: 1, OrderID: 2, Symbol: "IBM"},
+	}
+	if sent := client.cancelStale(stale); sent != 2 {
+		t.Errorf("expected 2 cancels queued, got %d", sent)
+	}
+
+	snap := client.Stats()
+	if snap.RateLimited != 0 || snap.RateDelayed != 1 || snap.CancelsSent != 2 {
+		t.Errorf("unexpected stats: %+v", snap)
+	}
+}
+
+func TestClient_RateLimit_Block(t *testing.T) {
+	limits := RateLimits{OrdersPerSec: 20, Burst: 1}
+	client := newTestClient(t, "localhost:1234", func(cfg *Config) { cfg.RateLimit = &limits })
+
+	if _, err := client.SendOrder(testOrder); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Next token is 50ms away
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := client.SendOrderContext(ctx, testOrder); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	start := time.Now()
+	if _, err := client.SendOrder(testOrder); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if waited := time.Since(start); waited < 20*time.Millisecond {
+		t.Errorf("expected send to wait for a token, waited %v", waited)
+	}
+
+	snap := client.Stats()
+	if snap.RateDelayed != 2 || snap.MessagesSent != 2 {
+		t.Errorf("unexpected stats: %+v", snap)
+	}
+}
@@ -0,0 +1,107 @@
+// Full path: pkg/meclient/transport/datagram.go
+
+package transport
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+// MaxDatagramSize is the largest UDP payload over IPv4 (65535 - 8 - 20).
+const MaxDatagramSize = 65507
+
+// datagramBufferSize leaves room to detect datagrams larger than allowed.
+const datagramBufferSize = MaxDatagramSize + 1
+
+// Datagram errors
+var (
+	// ErrMalformedDatagram is returned by the UDP reader when a datagram does
+	// not hold a whole number of valid frames. The datagram is dropped in
+	// full, so the stream stays aligned and reading may continue.
+	ErrMalformedDatagram = errors.New("malformed datagram")
+
+	// ErrDatagramTooLarge is returned when a write exceeds MaxDatagramSize.
+	ErrDatagramTooLarge = errors.New("datagram too large")
+)
+
+// datagramReader presents a UDP socket as a byte stream one datagram at a
+// time. Each datagram is validated to contain only complete frames before
+// any of its bytes are returned, so frames never span datagrams.
+type datagramReader struct {
+	conn    *net.UDPConn
+	buf     []byte
+	pending []byte
+}
+
+func newDatagramReader(conn *net.UDPConn) *datagramReader {
+	return &datagramReader{
+		conn: conn,
+		buf:  make([]byte, datagramBufferSize),
+	}
+}
+
+// Read returns bytes from the current datagram, receiving the next one
+// when the current datagram is exhausted.
+func (r *datagramReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		n, err := r.conn.Read(r.buf)
+		if err != nil {
+			return 0, err
+		}
+		if n == 0 {
+			continue
+		}
+		if err := validateDatagram(r.buf[:n]); err != nil {
+			return 0, err
+		}
+		r.pending = r.buf[:n]
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// validateDatagram checks that data is a sequence of complete
+// length-prefixed frames.
+func validateDatagram(data []byte) error {
+	if len(data) > MaxDatagramSize {
+		return fmt.Errorf("%w: %d bytes exceeds %d", ErrMalformedDatagram, len(data), MaxDatagramSize)
+	}
+
+	for off := 0; off < len(data); {
+		if len(data)-off < 4 {
+			return fmt.Errorf("%w: truncated frame header at offset %d", ErrMalformedDatagram, off)
+		}
+
+		length := int(binary.BigEndian.Uint32(data[off:]))
+		off += 4
+
+		if length == 0 || length > protocol.MaxFrameSize {
+			return fmt.Errorf("%w: invalid frame length %d", ErrMalformedDatagram, length)
+		}
+		if length > len(data)-off {
+			return fmt.Errorf("%w: frame of %d bytes truncated to %d", ErrMalformedDatagram, length, len(data)-off)
+		}
+		off += length
+	}
+
+	return nil
+}
+
+// datagramWriter sends each Write as a single datagram. Encoders must write
+// whole frames in one call, as the built-in encoders do.
+type datagramWriter struct {
+	conn *net.UDPConn
+}
+
+func (w datagramWriter) Write(p []byte) (int, error) {
+	if len(p) > MaxDatagramSize {
+		return 0, fmt.Errorf("%w: %d bytes", ErrDatagramTooLarge, len(p))
+	}
+	return w.conn.Write(p)
+}
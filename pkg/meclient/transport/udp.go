@@ -15,10 +15,14 @@ import (
 // UDP implements Transport over UDP.
 // Note: UDP is connectionless, so Connect() resolves the address
 // and Close() is a no-op for the underlying socket.
+//
+// Each datagram carries one or more complete length-prefixed frames.
+// Malformed datagrams are dropped whole and reported as ErrMalformedDatagram.
 type UDP struct {
 	cfg *config.Config
 
 	conn   *net.UDPConn
+	reader *datagramReader
 	addr   *net.UDPAddr
 	mu     sync.RWMutex
 	active bool
@@ -45,6 +49,7 @@ func (u *UDP) Connect() error {
 
 	u.mu.Lock()
 	u.conn = conn
+	u.reader = newDatagramReader(conn)
 	u.addr = addr
 	u.active = true
 	u.mu.Unlock()
@@ -62,23 +67,27 @@ func (u *UDP) Close() error {
 	if u.conn != nil {
 		err := u.conn.Close()
 		u.conn = nil
+		u.reader = nil
 		return err
 	}
 	return nil
 }
 
-// Reader returns the UDP connection as a reader.
+// Reader returns a datagram-aware reader that yields whole frames.
 func (u *UDP) Reader() io.Reader {
 	u.mu.RLock()
 	defer u.mu.RUnlock()
-	return u.conn
+	if u.reader == nil {
+		return nil
+	}
+	return u.reader
 }
 
-// Writer returns the UDP connection as a writer.
+// Writer returns a writer that sends each Write as one datagram.
 func (u *UDP) Writer() io.Writer {
 	u.mu.RLock()
 	defer u.mu.RUnlock()
-	return u.conn
+	return datagramWriter{conn: u.conn}
 }
 
 // IsConnected returns true if active.
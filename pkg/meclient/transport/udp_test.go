@@ -0,0 +1,188 @@
+// Full path: pkg/meclient/transport/udp_test.go
+
+package transport
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/config"
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient/protocol"
+)
+
+func frame(payload string) []byte {
+	buf := make([]byte, 4+len(payload))
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(payload)))
+	copy(buf[4:], payload)
+	return buf
+}
+
+// startUDPServer returns a local UDP listener and a connected client transport.
+// The client sends a hello datagram so the server learns its address.
+func startUDPServer(t *testing.T) (*net.UDPConn, *net.UDPAddr, *UDP) {
+	t.Helper()
+
+	server, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+
+	cfg := &config.Config{
+		Address:        server.LocalAddr().String(),
+		ConnectTimeout: time.Second,
+	}
+	udp := NewUDP(cfg)
+	if err := udp.Connect(); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { udp.Close() })
+
+	if _, err := udp.Writer().Write(frame("F\n")); err != nil {
+		t.Fatalf("hello write error: %v", err)
+	}
+
+	buf := make([]byte, 64)
+	_ = server.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, clientAddr, err := server.ReadFromUDP(buf)
+	if err != nil {
+		t.Fatalf("server read error: %v", err)
+	}
+
+	_ = udp.SetDeadline(time.Now().Add(2 * time.Second))
+	return server, clientAddr, udp
+}
+
+func TestUDPWrite_OneDatagramPerFrame(t *testing.T) {
+	server, _, udp := startUDPServer(t)
+
+	enc := protocol.NewEncoder(udp.Writer())
+	order := &protocol.NewOrder{UserID: 1, Symbol: "IBM", Price: 100, Qty: 50, Side: protocol.SideBuy, OrderID: 7}
+	if err := enc.EncodeNewOrder(order); err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	if err := enc.EncodeCancel(&protocol.CancelOrder{UserID: 1, OrderID: 7}); err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+
+	want := []string{"N,1,IBM,100,50,B,7\n", "C,1,7\n"}
+	buf := make([]byte, 1024)
+	for _, payload := range want {
+		n, _, err := server.ReadFromUDP(buf)
+		if err != nil {
+			t.Fatalf("server read error: %v", err)
+		}
+		if got := string(buf[:n]); got != string(frame(payload)) {
+			t.Errorf("expected datagram %q, got %q", frame(payload), got)
+		}
+	}
+}
+
+func TestUDPRead_MultipleFramesPerDatagram(t *testing.T) {
+	server, clientAddr, udp := startUDPServer(t)
+
+	datagram := append(frame("A, IBM, 1, 1001"), frame("T, IBM, 1, 1001, 2, 2001, 100, 50")...)
+	if _, err := server.WriteToUDP(datagram, clientAddr); err != nil {
+		t.Fatalf("server write error: %v", err)
+	}
+	if _, err := server.WriteToUDP(frame("C, IBM, 1, 1001"), clientAddr); err != nil {
+		t.Fatalf("server write error: %v", err)
+	}
+
+	dec := protocol.NewDecoder(udp.Reader())
+
+	msg, err := dec.Decode()
+	if err != nil || msg.Ack == nil {
+		t.Fatalf("expected ack, got %+v (err %v)", msg, err)
+	}
+	msg, err = dec.Decode()
+	if err != nil || msg.Trade == nil {
+		t.Fatalf("expected trade from same datagram, got %+v (err %v)", msg, err)
+	}
+	msg, err = dec.Decode()
+	if err != nil || msg.CancelAck == nil {
+		t.Fatalf("expected cancel ack from next datagram, got %+v (err %v)", msg, err)
+	}
+}
+
+func TestUDPRead_MalformedDatagramDropped(t *testing.T) {
+	server, clientAddr, udp := startUDPServer(t)
+
+	good := frame("A, IBM, 1, 1001")
+	truncated := append(frame("A, IBM, 1, 1"), good[:len(good)-3]...)
+	oversize := make([]byte, 8)
+	binary.BigEndian.PutUint32(oversize, protocol.MaxFrameSize+1)
+
+	for _, datagram := range [][]byte{truncated, oversize, good} {
+		if _, err := server.WriteToUDP(datagram, clientAddr); err != nil {
+			t.Fatalf("server write error: %v", err)
+		}
+	}
+
+	dec := protocol.NewDecoder(udp.Reader())
+
+	for i := 0; i < 2; i++ {
+		if _, err := dec.Decode(); !errors.Is(err, ErrMalformedDatagram) {
+			t.Fatalf("datagram %d: expected ErrMalformedDatagram, got %v", i, err)
+		}
+	}
+
+	// The stream is still aligned for the next datagram
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if msg.Ack == nil || msg.Ack.OrderID != 1001 {
+		t.Errorf("expected ack 1001, got %+v", msg)
+	}
+}
+
+func TestUDPWrite_TooLarge(t *testing.T) {
+	_, _, udp := startUDPServer(t)
+
+	_, err := udp.Writer().Write(make([]byte, MaxDatagramSize+1))
+	if !errors.Is(err, ErrDatagramTooLarge) {
+		t.Errorf("expected ErrDatagramTooLarge, got %v", err)
+	}
+}
+
+func TestUDPReader_NotConnected(t *testing.T) {
+	udp := NewUDP(&config.Config{Address: "localhost:1234"})
+
+	if udp.Reader() != nil {
+		t.Error("reader should be nil when not connected")
+	}
+}
+
+func TestValidateDatagram(t *testing.T) {
+	ack := frame("A, IBM, 1, 1001")
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{"single frame", ack, false},
+		{"two frames", append(append([]byte{}, ack...), ack...), false},
+		{"short header", ack[:3], true},
+		{"trailing partial header", append(append([]byte{}, ack...), 0, 0), true},
+		{"truncated payload", ack[:len(ack)-1], true},
+		{"zero length", make([]byte, 4), true},
+		{"oversize", make([]byte, MaxDatagramSize+1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDatagram(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("unexpected error state: %v", err)
+			}
+			if err != nil && !errors.Is(err, ErrMalformedDatagram) {
+				t.Errorf("expected ErrMalformedDatagram, got %v", err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@
 package scenarios
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,21 +12,24 @@ import (
 
 // Runner executes scenarios against a matching engine client
 type Runner struct {
-	client      *meclient.Client
-	userID      uint32
-	nextOrderID uint32
-	verbose     bool
-	result      *Result
+	client  *meclient.Client
+	userID  uint32
+	verbose bool
+	result  *Result
 }
 
-// NewRunner creates a new scenario runner
+// ErrEventsRequired is returned by Run when the client does not deliver
+// events, since responses are counted from Events()
+var ErrEventsRequired = errors.New("scenarios need a client delivering events (DeliverEvents or DeliverBoth, no Handler)")
+
+// NewRunner creates a new scenario runner. The client must deliver
+// events (DeliverEvents or DeliverBoth, no Handler); Run checks it
 func NewRunner(client *meclient.Client, userID uint32, verbose bool) *Runner {
 	return &Runner{
-		client:      client,
-		userID:      userID,
-		nextOrderID: 1,
-		verbose:     verbose,
-		result:      &Result{},
+		client:  client,
+		userID:  userID,
+		verbose: verbose,
+		result:  &Result{},
 	}
 }
 
@@ -40,9 +44,16 @@ func (r *Runner) Run(scenarioID int, dangerBurst bool) (*Result, error) {
 		return nil, fmt.Errorf("scenario %d requires --danger-burst flag", scenarioID)
 	}
 
+	if r.client != nil && !r.client.DeliversEvents() {
+		return nil, ErrEventsRequired
+	}
+
 	// Reset state
-	r.nextOrderID = 1
 	r.result = &Result{}
+	if r.client != nil && r.client.Latency() != nil {
+		r.client.Latency().Reset()
+		r.client.LatencyInterval() // discard latency from before the run
+	}
 
 	switch scenarioID {
 	// Basic
@@ -96,24 +107,34 @@ func (r *Runner) Run(scenarioID int, dangerBurst bool) (*Result, error) {
 	}
 }
 
-// sendOrder sends an order and tracks the result
-func (r *Runner) sendOrder(symbol string, price, qty uint32, side meclient.Side) error {
+// finish stamps the end time, fills in latency and computes throughput
+func (r *Runner) finish() {
+	r.result.EndTime = time.Now()
+	if r.client != nil && r.client.Latency() != nil {
+		_, ack := r.client.LatencyInterval()
+		r.result.SetLatency(ack)
+	}
+	r.result.Finalize()
+}
+
+// sendOrder sends an order, tracks the result and returns the order ID
+// the client assigned
+func (r *Runner) sendOrder(symbol string, price, qty uint32, side meclient.Side) (uint32, error) {
 	order := meclient.NewOrder{
-		UserID:  r.userID,
-		Symbol:  symbol,
-		Price:   price,
-		Qty:     qty,
-		Side:    side,
-		OrderID: r.nextOrderID,
+		UserID: r.userID,
+		Symbol: symbol,
+		Price:  price,
+		Qty:    qty,
+		Side:   side,
 	}
-	r.nextOrderID++
 
-	if err := r.client.SendOrder(order); err != nil {
+	orderID, err := r.client.SendOrder(order)
+	if err != nil {
 		r.result.OrdersFailed++
-		return err
+		return 0, err
 	}
 	r.result.OrdersSent++
-	return nil
+	return orderID, nil
 }
 
 // sendCancel sends a cancel request
@@ -130,42 +151,13 @@ func (r *Runner) sendFlush() error {
 	return r.client.SendFlush()
 }
 
-// drainResponses waits for and counts responses
+// drainResponses waits for and counts responses, in wire order
 func (r *Runner) drainResponses(timeout time.Duration) {
 	deadline := time.After(timeout)
 	for {
 		select {
-		case ack := <-r.client.Acks():
-			r.result.ResponsesReceived++
-			if r.verbose {
-				fmt.Printf("[RECV] A, %s, %d, %d\n", ack.Symbol, ack.UserID, ack.OrderID)
-			}
-		case trade := <-r.client.Trades():
-			r.result.ResponsesReceived++
-			r.result.TradesExecuted++
-			if r.verbose {
-				fmt.Printf("[RECV] T, %s, %d, %d, %d, %d, %d, %d\n",
-					trade.Symbol,
-					trade.BuyUserID, trade.BuyOrderID,
-					trade.SellUserID, trade.SellOrderID,
-					trade.Price, trade.Qty)
-			}
-		case update := <-r.client.BookUpdates():
-			r.result.ResponsesReceived++
-			if r.verbose {
-				if update.Price == 0 && update.Qty == 0 {
-					fmt.Printf("[RECV] B, %s, %c, -, -\n", update.Symbol, update.Side)
-				} else {
-					fmt.Printf("[RECV] B, %s, %c, %d, %d\n",
-						update.Symbol, update.Side, update.Price, update.Qty)
-				}
-			}
-		case cancelAck := <-r.client.CancelAcks():
-			r.result.ResponsesReceived++
-			if r.verbose {
-				fmt.Printf("[RECV] C, %s, %d, %d\n",
-					cancelAck.Symbol, cancelAck.UserID, cancelAck.OrderID)
-			}
+		case ev := <-r.client.Events():
+			r.countEvent(ev)
 		case <-r.client.Errors():
 			// Count but don't print unless verbose
 		case <-deadline:
@@ -174,6 +166,53 @@ func (r *Runner) drainResponses(timeout time.Duration) {
 	}
 }
 
+// countEvent records one response
+func (r *Runner) countEvent(ev meclient.Event) {
+	switch ev.Kind {
+	case meclient.EventAck:
+		r.result.ResponsesReceived++
+		if r.verbose {
+			fmt.Printf("[RECV] A, %s, %d, %d\n", ev.Ack.Symbol, ev.Ack.UserID, ev.Ack.OrderID)
+		}
+	case meclient.EventTrade:
+		trade := ev.Trade
+		r.result.ResponsesReceived++
+		r.result.TradesExecuted++
+		if r.verbose {
+			fmt.Printf("[RECV] T, %s, %d, %d, %d, %d, %d, %d\n",
+				trade.Symbol,
+				trade.BuyUserID, trade.BuyOrderID,
+				trade.SellUserID, trade.SellOrderID,
+				trade.Price, trade.Qty)
+		}
+	case meclient.EventBookUpdate:
+		update := ev.BookUpdate
+		r.result.ResponsesReceived++
+		if r.verbose {
+			if update.Price == 0 && update.Qty == 0 {
+				fmt.Printf("[RECV] B, %s, %c, -, -\n", update.Symbol, update.Side)
+			} else {
+				fmt.Printf("[RECV] B, %s, %c, %d, %d\n",
+					update.Symbol, update.Side, update.Price, update.Qty)
+			}
+		}
+	case meclient.EventCancelAck:
+		cancelAck := ev.CancelAck
+		r.result.ResponsesReceived++
+		if r.verbose {
+			fmt.Printf("[RECV] C, %s, %d, %d\n",
+				cancelAck.Symbol, cancelAck.UserID, cancelAck.OrderID)
+		}
+	case meclient.EventReject:
+		reject := ev.Reject
+		r.result.ResponsesReceived++
+		if r.verbose {
+			fmt.Printf("[RECV] R, %s, %d, %d, %s\n",
+				reject.Symbol, reject.UserID, reject.OrderID, reject.Reason)
+		}
+	}
+}
+
 // SimpleOrders runs scenario 1: simple orders with no matching
 func (r *Runner) SimpleOrders() (*Result, error) {
 	fmt.Println("=== Scenario 1: Simple Orders ===")
@@ -183,7 +222,7 @@ func (r *Runner) SimpleOrders() (*Result, error) {
 
 	// Buy order
 	fmt.Println("Sending: BUY IBM 50@100")
-	if err := r.sendOrder("IBM", 100, 50, meclient.SideBuy); err != nil {
+	if _, err := r.sendOrder("IBM", 100, 50, meclient.SideBuy); err != nil {
 		return nil, err
 	}
 	time.Sleep(100 * time.Millisecond)
@@ -191,7 +230,7 @@ func (r *Runner) SimpleOrders() (*Result, error) {
 
 	// Sell order at different price (no match)
 	fmt.Println("\nSending: SELL IBM 50@105")
-	if err := r.sendOrder("IBM", 105, 50, meclient.SideSell); err != nil {
+	if _, err := r.sendOrder("IBM", 105, 50, meclient.SideSell); err != nil {
 		return nil, err
 	}
 	time.Sleep(100 * time.Millisecond)
@@ -208,8 +247,7 @@ func (r *Runner) SimpleOrders() (*Result, error) {
 	time.Sleep(200 * time.Millisecond)
 	r.drainResponses(500 * time.Millisecond)
 
-	r.result.EndTime = time.Now()
-	r.result.Finalize()
+	r.finish()
 	return r.result, nil
 }
 
@@ -222,7 +260,7 @@ func (r *Runner) MatchingTrade() (*Result, error) {
 
 	// Buy order
 	fmt.Println("Sending: BUY IBM 50@100")
-	if err := r.sendOrder("IBM", 100, 50, meclient.SideBuy); err != nil {
+	if _, err := r.sendOrder("IBM", 100, 50, meclient.SideBuy); err != nil {
 		return nil, err
 	}
 	time.Sleep(100 * time.Millisecond)
@@ -230,14 +268,13 @@ func (r *Runner) MatchingTrade() (*Result, error) {
 
 	// Matching sell order
 	fmt.Println("\nSending: SELL IBM 50@100 (should match!)")
-	if err := r.sendOrder("IBM", 100, 50, meclient.SideSell); err != nil {
+	if _, err := r.sendOrder("IBM", 100, 50, meclient.SideSell); err != nil {
 		return nil, err
 	}
 	time.Sleep(100 * time.Millisecond)
 	r.drainResponses(300 * time.Millisecond)
 
-	r.result.EndTime = time.Now()
-	r.result.Finalize()
+	r.finish()
 	return r.result, nil
 }
 
@@ -250,10 +287,10 @@ func (r *Runner) CancelOrder() (*Result, error) {
 
 	// Buy order
 	fmt.Println("Sending: BUY IBM 50@100")
-	if err := r.sendOrder("IBM", 100, 50, meclient.SideBuy); err != nil {
+	orderID, err := r.sendOrder("IBM", 100, 50, meclient.SideBuy)
+	if err != nil {
 		return nil, err
 	}
-	orderID := r.nextOrderID - 1
 	time.Sleep(100 * time.Millisecond)
 	r.drainResponses(100 * time.Millisecond)
 
@@ -265,8 +302,7 @@ func (r *Runner) CancelOrder() (*Result, error) {
 	time.Sleep(100 * time.Millisecond)
 	r.drainResponses(300 * time.Millisecond)
 
-	r.result.EndTime = time.Now()
-	r.result.Finalize()
+	r.finish()
 	return r.result, nil
 }
 
@@ -312,6 +348,12 @@ func (r *Runner) StressTest(count uint32, throttled bool) (*Result, error) {
 		delayMs = 0
 	}
 
+	// A client rate limiter paces every send, so no batch delays are needed
+	if throttled && r.client.RateLimiter() != nil {
+		batchSize, delayMs = count, 0
+		fmt.Printf("Paced by the client rate limiter\n\n")
+	}
+
 	if throttled && delayMs > 0 {
 		fmt.Printf("Batched mode: %d orders/batch, %d ms delay\n\n", batchSize, delayMs)
 	}
@@ -363,8 +405,7 @@ func (r *Runner) StressTest(count uint32, throttled bool) (*Result, error) {
 	time.Sleep(flushWait)
 	r.drainResponses(100 * time.Millisecond)
 
-	r.result.EndTime = time.Now()
-	r.result.Finalize()
+	r.finish()
 	r.result.Print()
 	return r.result, nil
 }
@@ -408,8 +449,7 @@ func (r *Runner) MatchingStress(pairs uint32) (*Result, error) {
 	time.Sleep(500 * time.Millisecond)
 	r.drainResponses(100 * time.Millisecond)
 
-	r.result.EndTime = time.Now()
-	r.result.Finalize()
+	r.finish()
 	r.result.Print()
 	return r.result, nil
 }
@@ -446,7 +486,7 @@ func (r *Runner) MultiSymbolStress(count uint32) (*Result, error) {
 			side = meclient.SideSell
 		}
 
-		if err := r.sendOrder(symbol, price, 10, side); err == nil {
+		if _, err := r.sendOrder(symbol, price, 10, side); err == nil {
 			// Track processor distribution (A-M = first 5, N-Z = last 5)
 			if symbolIdx < 5 {
 				proc0Count++
@@ -471,8 +511,7 @@ func (r *Runner) MultiSymbolStress(count uint32) (*Result, error) {
 	time.Sleep(200 * time.Millisecond)
 	r.drainResponses(100 * time.Millisecond)
 
-	r.result.EndTime = time.Now()
-	r.result.Finalize()
+	r.finish()
 	r.result.Print()
 	return r.result, nil
 }
@@ -7,6 +7,8 @@ package scenarios
 import (
 	"fmt"
 	"time"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient"
 )
 
 // Category represents the type of scenario
@@ -41,9 +43,13 @@ type Result struct {
 	EndTime   time.Time
 	Duration  time.Duration
 
-	MinLatency time.Duration
-	AvgLatency time.Duration
-	MaxLatency time.Duration
+	MinLatency  time.Duration
+	AvgLatency  time.Duration
+	P50Latency  time.Duration
+	P90Latency  time.Duration
+	P99Latency  time.Duration
+	P999Latency time.Duration
+	MaxLatency  time.Duration
 
 	OrdersPerSec   float64
 	MessagesPerSec float64
@@ -189,6 +195,10 @@ func (r *Result) Print() {
 		fmt.Println("Latency (round-trip):")
 		fmt.Printf("  Min:               %.3f µs\n", float64(r.MinLatency.Nanoseconds())/1000)
 		fmt.Printf("  Avg:               %.3f µs\n", float64(r.AvgLatency.Nanoseconds())/1000)
+		fmt.Printf("  p50:               %.3f µs\n", float64(r.P50Latency.Nanoseconds())/1000)
+		fmt.Printf("  p90:               %.3f µs\n", float64(r.P90Latency.Nanoseconds())/1000)
+		fmt.Printf("  p99:               %.3f µs\n", float64(r.P99Latency.Nanoseconds())/1000)
+		fmt.Printf("  p99.9:             %.3f µs\n", float64(r.P999Latency.Nanoseconds())/1000)
 		fmt.Printf("  Max:               %.3f µs\n", float64(r.MaxLatency.Nanoseconds())/1000)
 		fmt.Println()
 	}
@@ -201,6 +211,20 @@ func (r *Result) Print() {
 	}
 }
 
+// SetLatency fills in the round-trip latency fields from a histogram snapshot
+func (r *Result) SetLatency(s meclient.LatencySnapshot) {
+	if s.Count == 0 {
+		return
+	}
+	r.MinLatency = s.Min
+	r.AvgLatency = s.Mean()
+	r.P50Latency = s.P50()
+	r.P90Latency = s.P90()
+	r.P99Latency = s.P99()
+	r.P999Latency = s.P999()
+	r.MaxLatency = s.Max
+}
+
 // Finalize calculates derived fields like throughput
 func (r *Result) Finalize() {
 	r.Duration = r.EndTime.Sub(r.StartTime)
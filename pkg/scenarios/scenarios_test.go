@@ -5,6 +5,8 @@ package scenarios
 import (
 	"testing"
 	"time"
+
+	"github.com/tembolo1284/matching-engine-go-client/pkg/meclient"
 )
 
 func TestGetInfo_ValidScenarios(t *testing.T) {
@@ -132,6 +134,28 @@ func TestResultFinalizeZeroDuration(t *testing.T) {
 	}
 }
 
+func TestResultSetLatency(t *testing.T) {
+	r := &Result{}
+
+	r.SetLatency(meclient.LatencySnapshot{})
+	if r.MinLatency != 0 || r.P99Latency != 0 || r.MaxLatency != 0 {
+		t.Errorf("expected no latency from an empty snapshot, got %+v", r)
+	}
+
+	r.SetLatency(meclient.LatencySnapshot{
+		Count: 10,
+		Sum:   350 * time.Microsecond,
+		Min:   20 * time.Microsecond,
+		Max:   90 * time.Microsecond,
+	})
+	if r.MinLatency != 20*time.Microsecond || r.AvgLatency != 35*time.Microsecond || r.MaxLatency != 90*time.Microsecond {
+		t.Errorf("unexpected latency: min=%v avg=%v max=%v", r.MinLatency, r.AvgLatency, r.MaxLatency)
+	}
+	if r.P50Latency == 0 || r.P999Latency > r.MaxLatency {
+		t.Errorf("unexpected percentiles: p50=%v p99.9=%v", r.P50Latency, r.P999Latency)
+	}
+}
+
 func TestNewRunner(t *testing.T) {
 	runner := NewRunner(nil, 123, true)
 
@@ -144,9 +168,6 @@ func TestNewRunner(t *testing.T) {
 	if !runner.verbose {
 		t.Error("expected verbose to be true")
 	}
-	if runner.nextOrderID != 1 {
-		t.Errorf("expected nextOrderID 1, got %d", runner.nextOrderID)
-	}
 }
 
 func TestRunInvalidScenario(t *testing.T) {
@@ -157,3 +178,17 @@ func TestRunInvalidScenario(t *testing.T) {
 		t.Error("expected error for invalid scenario")
 	}
 }
+
+func TestRunRequiresEvents(t *testing.T) {
+	cfg := meclient.DefaultConfig("localhost:1234")
+	cfg.Delivery = meclient.DeliverChannels
+	client, err := meclient.New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := NewRunner(client, 1, false).Run(1, false); err != ErrEventsRequired {
+		t.Errorf("expected ErrEventsRequired, got %v", err)
+	}
+}